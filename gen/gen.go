@@ -0,0 +1,132 @@
+// Package gen generates wrappers of interfaces recording OpenTelemetry spans,
+// the same as the otelwrap command, for tools embedding the generator
+package gen
+
+import (
+	"github.com/QuangTung97/otelwrap/internal/generate"
+	"io"
+)
+
+// Error is an error with the stage of the generation process where it happened
+type Error = generate.Error
+
+// Stage is the step of the generation process where an error happened
+type Stage = generate.Stage
+
+// Stages of the generation process
+const (
+	StageArgs     = generate.StageArgs
+	StageLoad     = generate.StageLoad
+	StageFind     = generate.StageFind
+	StageGenerate = generate.StageGenerate
+	StageFormat   = generate.StageFormat
+)
+
+// Diagnostic is a note about how a method of an interface is wrapped,
+// e.g. the method is not traced because it has no context param
+type Diagnostic = generate.Diagnostic
+
+// Config specifies what is generated, the same as the flags of the otelwrap command
+type Config struct {
+	Options         bool   // constructors accepting options of the support package instead of a tracer and a prefix
+	CodeAttributes  bool   // the code.function and code.namespace attributes
+	CodeLocation    bool   // also the code.filepath and code.lineno attributes
+	SpanKind        string // span kind of methods without the kind directive
+	AllMethods      bool   // also trace methods without context.Context as the first param
+	NoEmbed         bool   // store the implementation in a private field instead of embedding it
+	Streaming       bool   // end the spans when returned readers, channels or iterators are consumed
+	Callbacks       bool   // run the invocations of func-typed params and results in child spans
+	Metrics         bool   // also generate wrappers recording metrics
+	RecordPanics    bool   // record panics as errors of the spans, then re-panic
+	ErrorClassifier bool   // add an error classifier to the constructors
+
+	Match        string              // regular expression selecting the interfaces of the package by name
+	Exclude      string              // regular expression excluding interfaces selected by Match or by glob patterns
+	SkipMethods  []string            // methods that are not traced, of the form Method or Interface.Method
+	IgnoreErrors []string            // sentinel errors that do not fail the spans, e.g. database/sql.ErrNoRows
+	TypeArgs     map[string][]string // type arguments of generic interfaces, by interface name
+	Template     string              // text of a custom template, empty for the built-in template
+	NamePrefix   string              // prefix of the names of the generated structs
+}
+
+// configOption is an option added if a field of the config is set
+type configOption struct {
+	enabled bool
+	option  Option
+}
+
+func (c Config) configOptions() []configOption {
+	return []configOption{
+		{c.Options, generate.WithOptionsConstructor()},
+		{c.CodeAttributes, generate.WithCodeAttributes()},
+		{c.CodeLocation, generate.WithCodeLocation()},
+		{c.SpanKind != "", generate.WithSpanKind(c.SpanKind)},
+		{c.AllMethods, generate.WithAllMethods()},
+		{c.NoEmbed, generate.WithNoEmbed()},
+		{c.Streaming, generate.WithStreaming()},
+		{c.Callbacks, generate.WithCallbacks()},
+		{c.Metrics, generate.WithMetrics()},
+		{c.RecordPanics, generate.WithRecordPanics()},
+		{c.ErrorClassifier, generate.WithErrorClassifier()},
+		{c.Match != "", generate.WithMatch(c.Match)},
+		{c.Exclude != "", generate.WithExclude(c.Exclude)},
+		{len(c.SkipMethods) > 0, generate.WithSkipMethods(c.SkipMethods...)},
+		{len(c.IgnoreErrors) > 0, generate.WithIgnoreErrors(c.IgnoreErrors...)},
+		{c.Template != "", generate.WithTemplate(c.Template)},
+		{c.NamePrefix != "", generate.WithNamePrefix(c.NamePrefix)},
+	}
+}
+
+func (c Config) options() []Option {
+	var options []Option
+	for _, opt := range c.configOptions() {
+		if opt.enabled {
+			options = append(options, opt.option)
+		}
+	}
+	for interfaceName, typeArgs := range c.TypeArgs {
+		options = append(options, generate.WithTypeArgs(interfaceName, typeArgs...))
+	}
+	return options
+}
+
+// Result is a generated file
+type Result struct {
+	Source      []byte // formatted source of the file, including the header of generated files
+	Diagnostics []Diagnostic
+}
+
+// Generator generates wrappers in-process, it does not change after created,
+// so it can be used for many interfaces, including from multiple goroutines
+type Generator struct {
+	options []Option
+}
+
+// NewGenerator creates a generator, the options are applied after the config
+func NewGenerator(conf Config, options ...Option) *Generator {
+	return &Generator{
+		options: append(conf.options(), options...),
+	}
+}
+
+// Generate generates wrappers of the interfaces of the package matching the pattern,
+// e.g. ./internal/repo or github.com/acme/lib/store, in the same package if not specified
+// by the WithInAnotherPackage option. The options are applied after the options of the generator
+func (g *Generator) Generate(pattern string, interfaceNames []string, options ...Option) (Result, error) {
+	var diagnostics []Diagnostic
+	options = append(append([]Option(nil), g.options...), options...)
+	options = append(options, generate.WithDiagnostics(func(d Diagnostic) {
+		diagnostics = append(diagnostics, d)
+	}))
+
+	source, err := generate.Render(func(w io.Writer) error {
+		return generate.LoadAndGenerate(w, pattern, interfaceNames, options...)
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{
+		Source:      source,
+		Diagnostics: diagnostics,
+	}, nil
+}
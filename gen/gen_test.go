@@ -0,0 +1,74 @@
+package gen
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const helloPkgPath = "github.com/QuangTung97/otelwrap/internal/generate/hello"
+
+func TestGenerator_Generate(t *testing.T) {
+	g := NewGenerator(Config{Callbacks: true})
+
+	result, err := g.Generate(helloPkgPath, []string{"Subscriber", "Processor"}, WithInAnotherPackage("wrappers"))
+	assert.Equal(t, nil, err)
+
+	source := string(result.Source)
+	assert.True(t, strings.HasPrefix(source, "// Code generated by otelwrap; DO NOT EDIT.\n"))
+	assert.Contains(t, source, "\npackage wrappers\n")
+	assert.Contains(t, source, "func (w *SubscriberWrapper) Subscribe(")
+	assert.Contains(t, source, "func (w *ProcessorWrapper) DoA(")
+	assert.Contains(t, source, `w.prefix+"Subscribe.handler"`)
+
+	assert.Equal(t, []Diagnostic{
+		{
+			Interface: "Subscriber",
+			Method:    "Handle",
+			Message:   "not traced, the first param is not context.Context",
+		},
+	}, result.Diagnostics)
+}
+
+func TestGenerator_Generate_Many_Times(t *testing.T) {
+	g := NewGenerator(Config{AllMethods: true, NamePrefix: "Traced"})
+
+	first, err := g.Generate(helloPkgPath, []string{"Subscriber"})
+	assert.Equal(t, nil, err)
+	assert.Contains(t, string(first.Source), "type TracedSubscriberWrapper struct")
+	assert.Equal(t, []Diagnostic{
+		{
+			Interface: "Subscriber",
+			Method:    "Handle",
+			Message:   "traced with the context of request param 'r'",
+		},
+	}, first.Diagnostics)
+
+	second, err := g.Generate(helloPkgPath, []string{"Processor"})
+	assert.Equal(t, nil, err)
+	assert.Contains(t, string(second.Source), "type TracedProcessorWrapper struct")
+	assert.Equal(t, []Diagnostic(nil), second.Diagnostics)
+}
+
+func TestGenerator_Generate_Error(t *testing.T) {
+	g := NewGenerator(Config{SpanKind: "remote"})
+
+	_, err := g.Generate(helloPkgPath, []string{"Processor"})
+
+	var genErr *Error
+	assert.True(t, errors.As(err, &genErr))
+	assert.Equal(t, StageArgs, genErr.Stage)
+}
+
+func TestGenerator_Generate_With_Match(t *testing.T) {
+	g := NewGenerator(Config{Match: "^S", Exclude: "Handler$"})
+
+	result, err := g.Generate(helloPkgPath, nil, WithInAnotherPackage("wrappers"))
+	assert.Equal(t, nil, err)
+
+	source := string(result.Source)
+	assert.Contains(t, source, "type SimpleWrapper struct")
+	assert.Contains(t, source, "type SubscriberWrapper struct")
+	assert.NotContains(t, source, "type StreamHandlerWrapper struct")
+}
@@ -0,0 +1,124 @@
+package gen
+
+import (
+	"github.com/QuangTung97/otelwrap/internal/generate"
+	"io"
+)
+
+// Option changes the generated code, in addition to Config
+type Option = generate.Option
+
+// WithInAnotherPackage generates the code in another package with the name,
+// referencing the interfaces by their package names
+func WithInAnotherPackage(packageName string) Option {
+	return generate.WithInAnotherPackage(packageName)
+}
+
+// WithOptionsConstructor generates constructors accepting options of the package
+// github.com/QuangTung97/otelwrap/support instead of a tracer and a prefix
+func WithOptionsConstructor() Option {
+	return generate.WithOptionsConstructor()
+}
+
+// WithCodeAttributes adds the code.function and code.namespace attributes to every span
+func WithCodeAttributes() Option {
+	return generate.WithCodeAttributes()
+}
+
+// WithCodeLocation also adds the code.filepath and code.lineno attributes of the method declarations
+func WithCodeLocation() Option {
+	return generate.WithCodeLocation()
+}
+
+// WithSpanKind specifies the span kind of methods without the kind directive,
+// one of internal, server, client, producer or consumer
+func WithSpanKind(kind string) Option {
+	return generate.WithSpanKind(kind)
+}
+
+// WithAllMethods also traces methods without context.Context as the first param
+func WithAllMethods() Option {
+	return generate.WithAllMethods()
+}
+
+// WithNoEmbed stores the implementation in a private field instead of embedding the interface
+func WithNoEmbed() Option {
+	return generate.WithNoEmbed()
+}
+
+// WithStreaming ends the spans of methods returning io.Reader, io.ReadCloser, <-chan T or iter.Seq[T]
+// when the results are consumed instead of when the methods return
+func WithStreaming() Option {
+	return generate.WithStreaming()
+}
+
+// WithCallbacks runs the invocations of func-typed params and results in child spans
+func WithCallbacks() Option {
+	return generate.WithCallbacks()
+}
+
+// WithMetrics also generates a wrapper recording metrics for each interface
+func WithMetrics() Option {
+	return generate.WithMetrics()
+}
+
+// WithRecordPanics records panics of the wrapped methods as errors of the spans before re-panicking
+func WithRecordPanics() Option {
+	return generate.WithRecordPanics()
+}
+
+// WithErrorClassifier adds a classifier of errors to the constructors of the wrappers
+func WithErrorClassifier() Option {
+	return generate.WithErrorClassifier()
+}
+
+// WithSkipMethods does not trace the methods with the names, of the form Method or Interface.Method
+func WithSkipMethods(names ...string) Option {
+	return generate.WithSkipMethods(names...)
+}
+
+// WithIgnoreErrors ignores errors matching the sentinel errors, e.g. database/sql.ErrNoRows
+func WithIgnoreErrors(refs ...string) Option {
+	return generate.WithIgnoreErrors(refs...)
+}
+
+// WithTypeArgs generates a wrapper for the instantiation of a generic interface
+func WithTypeArgs(interfaceName string, typeArgs ...string) Option {
+	return generate.WithTypeArgs(interfaceName, typeArgs...)
+}
+
+// WithTemplate generates the code with a custom template instead of the built-in template
+func WithTemplate(text string) Option {
+	return generate.WithTemplate(text)
+}
+
+// WithNamePrefix adds a prefix to the names of the generated structs
+func WithNamePrefix(prefix string) Option {
+	return generate.WithNamePrefix(prefix)
+}
+
+// WithMatch also selects the interfaces of the package with names matching the regular expression
+func WithMatch(expr string) Option {
+	return generate.WithMatch(expr)
+}
+
+// WithExclude excludes the interfaces with names matching the regular expression
+// from the interfaces selected by glob patterns, e.g. Repo*, or by WithMatch
+func WithExclude(expr string) Option {
+	return generate.WithExclude(expr)
+}
+
+// WithMatchReport writes the interfaces selected by glob patterns or by WithMatch to w
+func WithMatchReport(w io.Writer) Option {
+	return generate.WithMatchReport(w)
+}
+
+// WithContextReport writes how the context of each method is obtained to w
+func WithContextReport(w io.Writer) Option {
+	return generate.WithContextReport(w)
+}
+
+// DefaultTemplate returns the built-in template, as a starting point for custom templates
+func DefaultTemplate() string {
+	return generate.DefaultTemplate()
+}
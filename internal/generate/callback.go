@@ -0,0 +1,221 @@
+package generate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// templateCallback wraps a func-typed param or result so that its invocations run in child spans
+type templateCallback struct {
+	Name     string // param or result holding the callback
+	Original string // variable holding the callback before wrapping
+	CtxName  string
+	SpanName string
+
+	ParentCtx    string // parent context of the span, the context param of the callback if it has one
+	SpanNameExpr string
+	StartOptions string // links the span to the span of the method, empty if the span is its child
+
+	ParamsString  string
+	ResultsString string
+	ArgsString    string
+
+	WithReturn        bool
+	WithError         bool
+	ResultsRecvString string
+	ErrString         string
+	ChosenOtelCodes   string
+}
+
+func hasCallbackContext(tuple tupleType) bool {
+	return findParamIndex(tuple.signature.params, recognizedTypeContext) >= 0
+}
+
+// isCallback returns true if the param or result is a func literal type that can be wrapped
+func isCallback(tuple tupleType) bool {
+	return tuple.signature != nil && !tuple.isVariadic
+}
+
+func findCallbacks(tuples []tupleType) []tupleType {
+	var result []tupleType
+	for _, tuple := range tuples {
+		if isCallback(tuple) {
+			result = append(result, tuple)
+		}
+	}
+	return result
+}
+
+// callbacksSupported returns true if the method has a context of its span for the parents of the callback spans
+func callbacksSupported(methodCtx methodContext) bool {
+	return methodCtx.strategy == contextStrategyParam || methodCtx.strategy == contextStrategyRequest
+}
+
+func callbacksContainErrors(method methodType) bool {
+	callbacks := append(findCallbacks(method.params), findCallbacks(method.results)...)
+	for _, callback := range callbacks {
+		if findParamIndex(callback.signature.results, recognizedTypeError) >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+//revive:disable-next-line:flag-parameter
+func containsCallbackErrors(info packageTypeInfo, allMethods bool) bool {
+	for _, interfaceDetail := range info.interfaces {
+		if interfaceDetail.funcType {
+			continue
+		}
+		for _, method := range interfaceDetail.methods {
+			if callbacksSupported(findMethodContext(method, allMethods)) && callbacksContainErrors(method) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// copyTupleList copies the tuples so that they can be renamed without changing the method
+func copyTupleList(tuples []tupleType) []tupleType {
+	if tuples == nil {
+		return nil
+	}
+	return append([]tupleType(nil), tuples...)
+}
+
+// replaceTracePackageName replaces the package name of an expression of the form: trace.Name
+func replaceTracePackageName(expr string, importController *importer) string {
+	return replacePackageName(expr, []tupleTypePkg{
+		{
+			path:  otelTracePkgPath,
+			begin: 0,
+			end:   len("trace"),
+		},
+	}, importController)
+}
+
+// callbackVars are the names of the variables shared by the wrapped callbacks of a method
+type callbackVars struct {
+	original  string
+	link      string // empty if no callback has a context
+	parentCtx string
+}
+
+func newTemplateCallback(
+	global map[string]struct{},
+	tuple tupleType,
+	vars callbackVars,
+	spanNameExpr string,
+	importController *importer,
+) templateCallback {
+	// names used inside the closure that must not be shadowed by its params
+	reserved := map[string]struct{}{}
+	for name := range global {
+		reserved[name] = struct{}{}
+	}
+	reserved[vars.original] = struct{}{}
+
+	withContext := hasCallbackContext(tuple)
+	if withContext {
+		reserved[vars.link] = struct{}{}
+	} else {
+		reserved[vars.parentCtx] = struct{}{}
+	}
+
+	params := copyTupleList(tuple.signature.params)
+	results := copyTupleList(tuple.signature.results)
+
+	local := map[string]recognizedType{}
+	for _, field := range append(params, results...) {
+		if _, existed := reserved[field.name]; !nameIsEmpty(field.name) && !existed {
+			local[field.name] = field.recognized
+		}
+	}
+	assignVariableNamesForMethod(reserved, local, methodType{params: params, results: results})
+
+	callback := templateCallback{
+		Name:     tuple.name,
+		Original: vars.original,
+		CtxName:  "_",
+		SpanName: getVariableName(reserved, local, 0, recognizedTypeSpan),
+
+		ParentCtx:    vars.parentCtx,
+		SpanNameExpr: spanNameExpr,
+
+		ArgsString: generateArgsString(params),
+	}
+	if withContext {
+		callback.CtxName = params[findParamIndex(params, recognizedTypeContext)].name
+		callback.ParentCtx = callback.CtxName
+		withLinks := fmt.Sprintf("trace.WithLinks(%s)", vars.link)
+		callback.StartOptions = ", " + replaceTracePackageName(withLinks, importController)
+	}
+
+	callback.ParamsString, callback.ResultsString = generateSignatureStrings(
+		methodType{params: params, results: results}, importController,
+	)
+
+	var recvVars []string
+	for _, result := range results {
+		recvVars = append(recvVars, result.name)
+		if result.recognized == recognizedTypeError {
+			callback.ErrString = result.name
+		}
+	}
+	callback.WithReturn = len(results) > 0
+	callback.WithError = callback.ErrString != ""
+	callback.ResultsRecvString = strings.Join(recvVars, ", ")
+	callback.ChosenOtelCodes = replacePackageName("codes.Error", []tupleTypePkg{
+		{
+			path:  otelCodesPkgPath,
+			begin: 0,
+			end:   len("codes"),
+		},
+	}, importController)
+	return callback
+}
+
+// setTemplateCallbacks wraps the func-typed params and results of a traced method
+func setTemplateCallbacks(
+	global map[string]struct{},
+	local map[string]recognizedType,
+	method methodType,
+	generated *templateMethod,
+	spanNameOf func(callbackName string) string,
+	importController *importer,
+) {
+	params := findCallbacks(method.params)
+	results := findCallbacks(method.results)
+	if len(params) == 0 && len(results) == 0 {
+		return
+	}
+
+	// each callback is wrapped in its own block, so that all of them can use the same name
+	vars := callbackVars{
+		original:  chooseVariableName(global, local, "callback"),
+		parentCtx: generated.CtxName,
+	}
+	for _, callback := range append(params, results...) {
+		if hasCallbackContext(callback) {
+			vars.link = chooseVariableName(global, local, "link")
+			local[vars.link] = recognizedTypeUnknown
+
+			generated.Link = fmt.Sprintf("%s := %s(%s)", vars.link,
+				replaceTracePackageName("trace.LinkFromContext", importController), generated.CtxName)
+			break
+		}
+	}
+
+	newCallback := func(tuple tupleType) templateCallback {
+		return newTemplateCallback(
+			global, tuple, vars, spanNameOf(method.name+"."+tuple.name), importController,
+		)
+	}
+	for _, param := range params {
+		generated.Callbacks = append(generated.Callbacks, newCallback(param))
+	}
+	for _, result := range results {
+		generated.ResultCallbacks = append(generated.ResultCallbacks, newCallback(result))
+	}
+}
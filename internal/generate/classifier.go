@@ -0,0 +1,86 @@
+package generate
+
+import (
+	"fmt"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"strings"
+)
+
+const supportPkgPath = "github.com/QuangTung97/otelwrap/support"
+
+// sentinelError is a package-level error variable, e.g. database/sql.ErrNoRows
+type sentinelError struct {
+	pkg  importInfo
+	name string
+}
+
+// splitSentinelError splits a reference of the form: import/path.Name
+func splitSentinelError(ref string) (pkgPath string, name string, err error) {
+	index := strings.LastIndexByte(ref, '.')
+	if index <= 0 || index == len(ref)-1 || strings.LastIndexByte(ref, '/') > index {
+		return "", "", fmt.Errorf("invalid sentinel error '%s', expected import/path.Name", ref)
+	}
+	return ref[:index], ref[index+1:], nil
+}
+
+var errorInterface = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+func loadSentinelErrors(refs []string) ([]sentinelError, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	var paths []string
+	for _, ref := range refs {
+		pkgPath, _, err := splitSentinelError(ref)
+		if err != nil {
+			return nil, WrapError(err, StageArgs, "")
+		}
+		paths = append(paths, pkgPath)
+	}
+
+	pkgList, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes,
+	}, paths...)
+	if err != nil {
+		return nil, WrapError(err, StageLoad, "")
+	}
+
+	pkgMap := map[string]*packages.Package{}
+	for _, pkg := range pkgList {
+		pkgMap[pkg.PkgPath] = pkg
+	}
+
+	result := make([]sentinelError, 0, len(refs))
+	for _, ref := range refs {
+		sentinel, err := lookupSentinelError(pkgMap, ref)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, sentinel)
+	}
+	return result, nil
+}
+
+func lookupSentinelError(pkgMap map[string]*packages.Package, ref string) (sentinelError, error) {
+	pkgPath, name, _ := splitSentinelError(ref)
+
+	pkg, ok := pkgMap[pkgPath]
+	if !ok || pkg.Types == nil {
+		return sentinelError{}, WrapError(fmt.Errorf("can not load package of sentinel error '%s'", ref), StageLoad, "")
+	}
+
+	variable, ok := pkg.Types.Scope().Lookup(name).(*types.Var)
+	if !ok || !variable.Exported() || !types.Implements(variable.Type(), errorInterface) {
+		return sentinelError{}, WrapError(fmt.Errorf("'%s' is not an exported error variable", ref), StageLoad, "")
+	}
+
+	return sentinelError{
+		pkg: importInfo{
+			name: pkg.Name,
+			path: pkgPath,
+		},
+		name: name,
+	}, nil
+}
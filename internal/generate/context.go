@@ -0,0 +1,123 @@
+package generate
+
+import (
+	"fmt"
+	"io"
+)
+
+// contextStrategy is how the parent context of the span of a method is obtained
+type contextStrategy int
+
+const (
+	// contextStrategyNone is for methods that are not traced
+	contextStrategyNone contextStrategy = iota
+	// contextStrategyParam uses a context.Context param
+	contextStrategyParam
+	// contextStrategyRequest uses the context of a *http.Request param
+	contextStrategyRequest
+	// contextStrategyWrapper uses the fallback context stored on the wrapper
+	contextStrategyWrapper
+)
+
+type methodContext struct {
+	strategy   contextStrategy
+	paramIndex int // index of the param for contextStrategyParam and contextStrategyRequest
+}
+
+func findParamIndex(params []tupleType, recognized recognizedType) int {
+	for i, param := range params {
+		if param.recognized == recognized {
+			return i
+		}
+	}
+	return -1
+}
+
+// findMethodContext returns how the context of a method is obtained,
+// only methods with context.Context as the first param are traced if allMethods is false
+//
+//revive:disable-next-line:flag-parameter
+func findMethodContext(method methodType, allMethods bool) methodContext {
+	if method.skipped {
+		return methodContext{strategy: contextStrategyNone}
+	}
+	if !allMethods {
+		if len(method.params) > 0 && method.params[0].recognized == recognizedTypeContext {
+			return methodContext{strategy: contextStrategyParam, paramIndex: 0}
+		}
+		return methodContext{strategy: contextStrategyNone}
+	}
+
+	if index := findParamIndex(method.params, recognizedTypeContext); index >= 0 {
+		return methodContext{strategy: contextStrategyParam, paramIndex: index}
+	}
+	if index := findParamIndex(method.params, recognizedTypeHTTPRequest); index >= 0 {
+		return methodContext{strategy: contextStrategyRequest, paramIndex: index}
+	}
+	return methodContext{strategy: contextStrategyWrapper}
+}
+
+func (c methodContext) describe(method methodType) string {
+	switch c.strategy {
+	case contextStrategyParam:
+		return fmt.Sprintf("context param '%s'", method.params[c.paramIndex].name)
+	case contextStrategyRequest:
+		return fmt.Sprintf("context of request param '%s'", method.params[c.paramIndex].name)
+	case contextStrategyWrapper:
+		return "fallback context of the wrapper"
+	default:
+		if method.skipped {
+			return "not traced, skipped"
+		}
+		return "not traced"
+	}
+}
+
+// writeContextReport writes how the context of each method of the interfaces is obtained
+//
+//revive:disable-next-line:flag-parameter
+func writeContextReport(w io.Writer, info packageTypeInfo, allMethods bool) {
+	for _, interfaceDetail := range info.interfaces {
+		for _, method := range interfaceDetail.methods {
+			methodCtx := findMethodContext(method, allMethods)
+			_, _ = fmt.Fprintf(w, "%s.%s: %s\n", interfaceDetail.name, method.name, methodCtx.describe(method))
+		}
+	}
+}
+
+// Diagnostic is a note about how a method of an interface is wrapped
+type Diagnostic struct {
+	Interface string
+	Method    string
+	Message   string
+}
+
+// findDiagnostics returns the methods that are not traced or are traced without a context param
+//
+//revive:disable-next-line:flag-parameter
+func findDiagnostics(info packageTypeInfo, allMethods bool) []Diagnostic {
+	var result []Diagnostic
+	for _, interfaceDetail := range info.interfaces {
+		for _, method := range interfaceDetail.methods {
+			methodCtx := findMethodContext(method, allMethods)
+			var message string
+			switch methodCtx.strategy {
+			case contextStrategyNone:
+				message = "not traced, the first param is not context.Context"
+				if method.skipped {
+					message = "not traced, skipped"
+				}
+			case contextStrategyParam:
+				continue
+			default:
+				message = "traced with the " + methodCtx.describe(method)
+			}
+			result = append(result, Diagnostic{
+				Interface: interfaceDetail.name,
+				Method:    method.name,
+				Message:   message,
+			})
+		}
+	}
+	return result
+}
@@ -0,0 +1,142 @@
+package generate
+
+import (
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// templateParam is a param or a result of a method, for custom templates
+type templateParam struct {
+	Name     string
+	Type     string // type with the package names used in the generated file, e.g. *sql.Tx
+	Kind     string // recognized kind of the type, see recognizedTypeKinds, empty if not recognized
+	Variadic bool
+	Func     bool // a func literal type, e.g. func(ctx context.Context) error
+}
+
+// templateDirective is a directive of a method, e.g. //otelwrap:log level=debug
+type templateDirective struct {
+	Name string
+	Args []string
+}
+
+// Value returns the value of the argument of the form key=value, empty if not found
+func (d templateDirective) Value(key string) string {
+	for _, arg := range d.Args {
+		k, v, ok := splitKeyValue(arg)
+		if ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+// Directive returns the first directive with the name, nil if not found
+func (m templateMethod) Directive(name string) *templateDirective {
+	for i := range m.Directives {
+		if m.Directives[i].Name == name {
+			return &m.Directives[i]
+		}
+	}
+	return nil
+}
+
+// recognizedTypeKinds are the values of templateParam.Kind
+var recognizedTypeKinds = map[recognizedType]string{
+	recognizedTypeContext:     "context",
+	recognizedTypeError:       "error",
+	recognizedTypeHTTPRequest: "request",
+	recognizedTypeReader:      "reader",
+	recognizedTypeReadCloser:  "readcloser",
+	recognizedTypeRecvChan:    "chan",
+	recognizedTypeSeq:         "seq",
+}
+
+func newTemplateParams(tuples []tupleType, importController *importer) []templateParam {
+	var result []templateParam
+	for _, tuple := range tuples {
+		result = append(result, templateParam{
+			Name:     tuple.name,
+			Type:     replacePackageName(tuple.typeStr, tuple.pkgList, importController),
+			Kind:     recognizedTypeKinds[tuple.recognized],
+			Variadic: tuple.isVariadic,
+			Func:     tuple.signature != nil,
+		})
+	}
+	return result
+}
+
+func newTemplateDirectives(directives []directive) []templateDirective {
+	var result []templateDirective
+	for _, d := range directives {
+		result = append(result, templateDirective{
+			Name: d.name,
+			Args: d.args,
+		})
+	}
+	return result
+}
+
+// toSnakeCase converts names like GetUserByID to get_user_by_id
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+	var buf strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && !unicode.IsUpper(runes[i-1])
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if i > 0 && runes[i-1] != '_' && (prevLower || nextLower) {
+				_ = buf.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		_, _ = buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// templateFuncs are the helper functions available in all templates
+var templateFuncs = template.FuncMap{
+	"join":       strings.Join,
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"snake":      toSnakeCase,
+	"quote":      strconv.Quote,
+	"hasPrefix":  strings.HasPrefix,
+	"hasSuffix":  strings.HasSuffix,
+	"trimPrefix": strings.TrimPrefix,
+	"trimSuffix": strings.TrimSuffix,
+	"replace":    strings.ReplaceAll,
+}
+
+// DefaultTemplate returns the built-in template, including the definitions of its nested templates,
+// as a starting point for custom templates
+func DefaultTemplate() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString(templateString)
+	for _, nested := range []struct {
+		name string
+		text string
+	}{
+		{name: "metrics", text: metricsTemplateString},
+		{name: "func", text: funcTemplateString},
+		{name: "callback", text: callbackTemplateString},
+	} {
+		_, _ = buf.WriteString("{{- define \"" + nested.name + "\" }}")
+		_, _ = buf.WriteString(nested.text)
+		_, _ = buf.WriteString("{{ end -}}\n")
+	}
+	return buf.String()
+}
+
+// parseCustomTemplate parses a user-supplied template,
+// the nested templates of the built-in template can be used or redefined
+func parseCustomTemplate(text string) (*template.Template, error) {
+	tmpl, err := resultTemplate.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return tmpl.New("custom").Parse(text)
+}
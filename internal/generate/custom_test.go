@@ -0,0 +1,132 @@
+package generate
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGenerateCode_With_Default_Template_As_Custom(t *testing.T) {
+	optionsList := [][]Option{
+		nil,
+		{WithMetrics(), WithRecordPanics(), WithCallbacks()},
+		{WithOptionsConstructor(), WithStreaming(), WithNoEmbed()},
+	}
+	for _, options := range optionsList {
+		var expected bytes.Buffer
+		err := LoadAndGenerate(&expected, "./hello",
+			[]string{"Subscriber", "StreamHandler", "HandlerFunc", "ResultHandler"}, options...)
+		assert.Equal(t, nil, err)
+
+		var actual bytes.Buffer
+		err = LoadAndGenerate(&actual, "./hello",
+			[]string{"Subscriber", "StreamHandler", "HandlerFunc", "ResultHandler"},
+			append(options, WithTemplate(DefaultTemplate()))...)
+		assert.Equal(t, nil, err)
+
+		assert.Equal(t, expected.String(), actual.String())
+	}
+}
+
+func TestGenerateCode_With_Custom_Template(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		imports: []importInfo{
+			{
+				path: "context",
+				name: "context",
+			},
+		},
+		interfaces: []interfaceInfo{
+			{
+				name: "UserRepo",
+				methods: []methodType{
+					{
+						name: "GetUserByID",
+						params: []tupleType{
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+							{
+								name:    "ids",
+								typeStr: "...int64",
+
+								isVariadic: true,
+							},
+						},
+						results: []tupleType{
+							{
+								name:       "",
+								typeStr:    "error",
+								recognized: recognizedTypeError,
+							},
+						},
+						directives: []directive{
+							{
+								name: "log",
+								args: []string{"level=debug", "sampled"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, WithTemplate(`package {{ .PackageName }}
+{{ range .Interfaces }}{{ range .Methods }}
+// {{ snake .Name }}:{{ range .Params }} {{ .Name }} {{ .Type }} kind={{ .Kind }} variadic={{ .Variadic }};{{ end }}
+// results:{{ range .Results }} {{ .Name }} {{ .Type }} kind={{ .Kind }};{{ end }}
+{{- with .Directive "log" }}
+// log: level={{ .Value "level" }} args={{ join .Args "," }}
+{{- end }}
+{{- with .Directive "missing" }}
+// missing
+{{- end }}
+{{ end }}{{ end -}}
+`))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `package example
+
+// get_user_by_id: ctx context.Context kind=context variadic=false; ids ...int64 kind= variadic=true;
+// results: err error kind=error;
+// log: level=debug args=level=debug,sampled
+`, buf.String())
+}
+
+func TestGenerateCode_With_Invalid_Custom_Template(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+	}, WithTemplate("{{ .Interfaces"))
+
+	var genErr *Error
+	assert.Equal(t, true, errors.As(err, &genErr))
+	assert.Equal(t, StageArgs, genErr.Stage)
+	assert.Equal(t, "template: custom:1: unclosed action", genErr.Err.Error())
+}
+
+func TestToSnakeCase(t *testing.T) {
+	table := []struct {
+		name   string
+		input  string
+		output string
+	}{
+		{name: "single word", input: "Get", output: "get"},
+		{name: "words", input: "GetUser", output: "get_user"},
+		{name: "acronym at end", input: "GetUserByID", output: "get_user_by_id"},
+		{name: "acronym at begin", input: "HTTPServer", output: "http_server"},
+		{name: "lower case", input: "getUser", output: "get_user"},
+		{name: "with underscore", input: "Get_User", output: "get_user"},
+	}
+	for _, e := range table {
+		t.Run(e.name, func(t *testing.T) {
+			assert.Equal(t, e.output, toSnakeCase(e.input))
+		})
+	}
+}
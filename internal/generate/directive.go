@@ -0,0 +1,257 @@
+package generate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+const directivePrefix = "//otelwrap:"
+
+const (
+	directiveAttr   = "attr"
+	directiveResult = "result"
+	directiveKind   = "kind"
+	directiveSkip   = "skip"
+)
+
+type directive struct {
+	pos  token.Pos
+	name string
+	args []string
+}
+
+// parseDirectives collects comments of the form //otelwrap:<name> <args...>
+func parseDirectives(groups ...*ast.CommentGroup) []directive {
+	var result []directive
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		for _, comment := range group.List {
+			if !strings.HasPrefix(comment.Text, directivePrefix) {
+				continue
+			}
+			fields := strings.Fields(comment.Text[len(directivePrefix):])
+			if len(fields) == 0 {
+				continue
+			}
+			result = append(result, directive{
+				pos:  comment.Slash,
+				name: fields[0],
+				args: fields[1:],
+			})
+		}
+	}
+	return result
+}
+
+func newDirectiveError(fset *token.FileSet, d directive, format string, args ...any) error {
+	return newPositionError(StageFind, fset.Position(d.pos), format, args...)
+}
+
+// splitKeyValue splits a directive argument of the form name=value
+func splitKeyValue(arg string) (key string, value string, ok bool) {
+	index := strings.IndexByte(arg, '=')
+	if index <= 0 || index == len(arg)-1 {
+		return "", "", false
+	}
+	return arg[:index], arg[index+1:], true
+}
+
+// spanAttribute is an attribute computed from a parameter or a result
+type spanAttribute struct {
+	key   string
+	index int // index of the param, or of the result for result attributes
+
+	constructor string // name of the function in the otel attribute package
+	conversion  string // type conversion applied to the value, empty if not needed
+	length      bool   // the value is the length of a slice or a map
+}
+
+func newStringerInterface() *types.Interface {
+	signature := types.NewSignatureType(nil, nil, nil, nil,
+		types.NewTuple(types.NewVar(token.NoPos, nil, "", types.Typ[types.String])),
+		false,
+	)
+	method := types.NewFunc(token.NoPos, nil, "String", signature)
+	return types.NewInterfaceType([]*types.Func{method}, nil).Complete()
+}
+
+var stringerInterface = newStringerInterface()
+
+// isStringerValue returns true for named types implementing fmt.Stringer that can not be nil,
+// attribute.Stringer calls String() without checking for nil, e.g. a nil fmt.Stringer result on the error path
+func isStringerValue(t types.Type) bool {
+	if _, isNamed := t.(*types.Named); !isNamed {
+		return false
+	}
+	switch t.Underlying().(type) {
+	case *types.Interface, *types.Pointer:
+		return false
+	}
+	return types.Implements(t, stringerInterface)
+}
+
+func attributeConstructorForType(t types.Type) (constructor string, conversion string, ok bool) {
+	if isStringerValue(t) {
+		return "Stringer", "", true
+	}
+
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return "", "", false
+	}
+
+	var target types.BasicKind
+	switch basic.Kind() {
+	case types.Bool:
+		constructor, target = "Bool", types.Bool
+	case types.String:
+		constructor, target = "String", types.String
+	case types.Int:
+		constructor, target = "Int", types.Int
+	case types.Int8, types.Int16, types.Int32, types.Int64,
+		types.Uint8, types.Uint16, types.Uint32:
+		// uint, uint64 and uintptr are not supported, values above MaxInt64 would overflow int64
+		constructor, target = "Int64", types.Int64
+	case types.Float32, types.Float64:
+		constructor, target = "Float64", types.Float64
+	default:
+		return "", "", false
+	}
+
+	if !types.Identical(t, types.Typ[target]) {
+		conversion = types.Typ[target].Name()
+	}
+	return constructor, conversion, true
+}
+
+func isSliceOrMap(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Slice, *types.Map:
+		return true
+	default:
+		return false
+	}
+}
+
+func findTupleIndex(tuples []tupleType, name string) int {
+	for i, tuple := range tuples {
+		if !nameIsEmpty(tuple.name) && tuple.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseAttributeDirective handles: //otelwrap:attr param1=attr.key1 param2=attr.key2
+func parseAttributeDirective(
+	d directive, fset *token.FileSet,
+	params []tupleType, signature *types.Signature,
+) ([]spanAttribute, error) {
+	return parseTupleAttributes(d, fset, "param", params, signature.Params())
+}
+
+// parseResultDirective handles: //otelwrap:result n=db.rows_affected
+func parseResultDirective(
+	d directive, fset *token.FileSet,
+	results []tupleType, signature *types.Signature,
+) ([]spanAttribute, error) {
+	return parseTupleAttributes(d, fset, "result", results, signature.Results())
+}
+
+// parseTupleAttributes parses the arguments of the form name=key, kind is either param or result
+func parseTupleAttributes(
+	d directive, fset *token.FileSet, kind string,
+	tuples []tupleType, vars *types.Tuple,
+) ([]spanAttribute, error) {
+	if len(d.args) == 0 {
+		return nil, newDirectiveError(fset, d, "missing arguments for directive '%s'", d.name)
+	}
+
+	var result []spanAttribute
+	for _, arg := range d.args {
+		name, key, ok := splitKeyValue(arg)
+		if !ok {
+			return nil, newDirectiveError(fset, d, "invalid argument '%s', expected %s=key", arg, kind)
+		}
+
+		index := findTupleIndex(tuples, name)
+		if index < 0 {
+			return nil, newDirectiveError(fset, d, "%s '%s' not found", kind, name)
+		}
+
+		varType := vars.At(index).Type()
+		constructor, conversion, ok := attributeConstructorForType(varType)
+		length := false
+		if !ok && isSliceOrMap(varType) {
+			constructor, length, ok = "Int", true, true
+		}
+		if !ok {
+			return nil, newDirectiveError(fset, d,
+				"unsupported type '%s' of %s '%s' for attribute", tuples[index].typeStr, kind, name)
+		}
+
+		result = append(result, spanAttribute{
+			key:         key,
+			index:       index,
+			constructor: constructor,
+			conversion:  conversion,
+			length:      length,
+		})
+	}
+	return result, nil
+}
+
+// spanKindConstants maps the span kinds to the constants of the otel trace package
+var spanKindConstants = map[string]string{
+	"internal": "SpanKindInternal",
+	"server":   "SpanKindServer",
+	"client":   "SpanKindClient",
+	"producer": "SpanKindProducer",
+	"consumer": "SpanKindConsumer",
+}
+
+func checkSpanKind(kind string) error {
+	if _, ok := spanKindConstants[kind]; !ok {
+		return fmt.Errorf("invalid span kind '%s', expected internal, server, client, producer or consumer", kind)
+	}
+	return nil
+}
+
+// findSkip handles: //otelwrap:skip
+func findSkip(fset *token.FileSet, groups ...*ast.CommentGroup) (bool, error) {
+	skipped := false
+	for _, d := range parseDirectives(groups...) {
+		if d.name != directiveSkip {
+			continue
+		}
+		if len(d.args) != 0 {
+			return false, newDirectiveError(fset, d, "directive '%s' expects no arguments", d.name)
+		}
+		skipped = true
+	}
+	return skipped, nil
+}
+
+// findSpanKind handles: //otelwrap:kind client
+// returns an empty string if there is no kind directive
+func findSpanKind(fset *token.FileSet, groups ...*ast.CommentGroup) (string, error) {
+	kind := ""
+	for _, d := range parseDirectives(groups...) {
+		if d.name != directiveKind {
+			continue
+		}
+		if len(d.args) != 1 {
+			return "", newDirectiveError(fset, d, "directive '%s' expects exactly one argument", d.name)
+		}
+		if err := checkSpanKind(d.args[0]); err != nil {
+			return "", newDirectiveError(fset, d, "%s", err.Error())
+		}
+		kind = d.args[0]
+	}
+	return kind, nil
+}
@@ -0,0 +1,89 @@
+package generate
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"testing"
+)
+
+func TestParseDirectives(t *testing.T) {
+	directives := parseDirectives(&ast.CommentGroup{
+		List: []*ast.Comment{
+			{Slash: 10, Text: "// GetUser ..."},
+			{Slash: 20, Text: "//otelwrap:attr id=user.id  name=user.name"},
+			{Slash: 30, Text: "// otelwrap:attr not=directive"},
+			{Slash: 40, Text: "//otelwrap:"},
+		},
+	}, nil)
+	assert.Equal(t, []directive{
+		{
+			pos:  20,
+			name: "attr",
+			args: []string{"id=user.id", "name=user.name"},
+		},
+	}, directives)
+}
+
+func TestFindSkip(t *testing.T) {
+	fset := token.NewFileSet()
+
+	skipped, err := findSkip(fset, &ast.CommentGroup{
+		List: []*ast.Comment{
+			{Slash: 10, Text: "// Get ..."},
+			{Slash: 20, Text: "//otelwrap:skip"},
+		},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, skipped)
+
+	skipped, err = findSkip(fset, nil, &ast.CommentGroup{
+		List: []*ast.Comment{
+			{Slash: 10, Text: "//otelwrap:kind client"},
+		},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, skipped)
+
+	_, err = findSkip(fset, &ast.CommentGroup{
+		List: []*ast.Comment{
+			{Slash: 10, Text: "//otelwrap:skip always"},
+		},
+	})
+	var genErr *Error
+	assert.Equal(t, true, errors.As(err, &genErr))
+	assert.Equal(t, "directive 'skip' expects no arguments", genErr.Err.Error())
+}
+
+func TestAttributeConstructorForType(t *testing.T) {
+	constructor, conversion, ok := attributeConstructorForType(types.Typ[types.Int])
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "Int", constructor)
+	assert.Equal(t, "", conversion)
+
+	constructor, conversion, ok = attributeConstructorForType(types.Typ[types.Uint32])
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "Int64", constructor)
+	assert.Equal(t, "int64", conversion)
+
+	constructor, conversion, ok = attributeConstructorForType(types.Typ[types.Float32])
+	assert.Equal(t, true, ok)
+	assert.Equal(t, "Float64", constructor)
+	assert.Equal(t, "float64", conversion)
+
+	_, _, ok = attributeConstructorForType(types.NewSlice(types.Typ[types.String]))
+	assert.Equal(t, false, ok)
+
+	for _, kind := range []types.BasicKind{types.Uint, types.Uint64, types.Uintptr} {
+		_, _, ok = attributeConstructorForType(types.Typ[kind])
+		assert.Equal(t, false, ok)
+	}
+}
+
+func TestAttributeConstructorForType_Stringer_Interface(t *testing.T) {
+	stringer := types.NewNamed(types.NewTypeName(token.NoPos, nil, "Stringer", nil), stringerInterface, nil)
+	_, _, ok := attributeConstructorForType(stringer)
+	assert.Equal(t, false, ok)
+}
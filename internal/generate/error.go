@@ -0,0 +1,91 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"strings"
+)
+
+// Stage is the step of the generation process where an error happened
+type Stage string
+
+const (
+	// StageArgs is for errors of the command line arguments
+	StageArgs Stage = "args"
+	// StageLoad is for errors when loading packages
+	StageLoad Stage = "load"
+	// StageFind is for errors when finding interfaces and their methods
+	StageFind Stage = "find"
+	// StageGenerate is for errors when executing the templates
+	StageGenerate Stage = "generate"
+	// StageFormat is for errors when formatting the generated source
+	StageFormat Stage = "format"
+	// StageWrite is for errors when writing the output file
+	StageWrite Stage = "write"
+	// StageCheck is for errors when comparing with the existing output file
+	StageCheck Stage = "check"
+)
+
+// Error is an error with the context of where it happened
+type Error struct {
+	Stage     Stage
+	Interface string         // empty if not related to a specific interface
+	Position  token.Position // invalid if the position is unknown
+	Err       error
+}
+
+func (e *Error) Error() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString(string(e.Stage))
+	if e.Interface != "" {
+		_, _ = fmt.Fprintf(&buf, " interface '%s'", e.Interface)
+	}
+	if e.Position.IsValid() {
+		_, _ = fmt.Fprintf(&buf, " at %s", e.Position)
+	}
+	_, _ = buf.WriteString(": ")
+	_, _ = buf.WriteString(e.Err.Error())
+	return buf.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WrapError adds the stage and the interface name to err,
+// keeping the details of an existing *Error
+func WrapError(err error, stage Stage, interfaceName string) error {
+	if err == nil {
+		return nil
+	}
+
+	var genErr *Error
+	if errors.As(err, &genErr) {
+		if genErr.Interface == "" {
+			genErr.Interface = interfaceName
+		}
+		return err
+	}
+	return &Error{
+		Stage:     stage,
+		Interface: interfaceName,
+		Err:       err,
+	}
+}
+
+func newPositionError(stage Stage, pos token.Position, format string, args ...any) error {
+	return &Error{
+		Stage:    stage,
+		Position: pos,
+		Err:      fmt.Errorf(format, args...),
+	}
+}
+
+func newInterfaceNotFoundError(interfaceName string) error {
+	return &Error{
+		Stage:     StageFind,
+		Interface: interfaceName,
+		Err:       fmt.Errorf("can not find interface '%s'", interfaceName),
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"io"
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
 )
 
 // FindResult ...
@@ -34,7 +36,7 @@ func FindPackage(filePath string, pkgName string) (FindResult, error) {
 	}
 
 	fset := token.NewFileSet()
-	f, err := parser.ParseFile(fset, filePath, string(data), 0)
+	f, err := parser.ParseFile(fset, filePath, string(data), parser.ImportsOnly)
 	if err != nil {
 		return FindResult{}, err
 	}
@@ -54,3 +56,58 @@ func FindPackage(filePath string, pkgName string) (FindResult, error) {
 	}
 	return FindResult{}, ErrNotFound
 }
+
+// FindPackageName returns the name of the package declared by the Go files in the directory, excluding test files
+func FindPackageName(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, name), nil, parser.PackageClauseOnly)
+		if err != nil {
+			return "", err
+		}
+		return f.Name.Name, nil
+	}
+	return "", ErrNotFound
+}
+
+// FindPackageInDir finds the package imported with the name by any Go file in the directory,
+// the files are searched in the order of their names, test files last
+func FindPackageInDir(dir string, pkgName string) (FindResult, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return FindResult{}, err
+	}
+
+	var files []string
+	var testFiles []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		if strings.HasSuffix(name, "_test.go") {
+			testFiles = append(testFiles, name)
+		} else {
+			files = append(files, name)
+		}
+	}
+
+	for _, name := range append(files, testFiles...) {
+		result, err := FindPackage(filepath.Join(dir, name), pkgName)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err == nil && strings.HasSuffix(result.SrcPkgName, "_test") {
+			continue // external test package
+		}
+		return result, err
+	}
+	return FindResult{}, ErrNotFound
+}
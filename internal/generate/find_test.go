@@ -19,3 +19,27 @@ func TestFindPackage_Not_Found(t *testing.T) {
 	assert.Equal(t, ErrNotFound, err)
 	assert.Equal(t, FindResult{}, result)
 }
+
+func TestFindPackageInDir(t *testing.T) {
+	result, err := FindPackageInDir("./hello", "http")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, FindResult{
+		SrcPkgName:  "hello",
+		DestPkgPath: "net/http",
+	}, result)
+}
+
+func TestFindPackageInDir_Not_Found(t *testing.T) {
+	result, err := FindPackageInDir("./hello", "random")
+	assert.Equal(t, ErrNotFound, err)
+	assert.Equal(t, FindResult{}, result)
+}
+
+func TestFindPackageName(t *testing.T) {
+	name, err := FindPackageName("./hello")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "hello", name)
+
+	_, err = FindPackageName("./hello/otel/missing")
+	assert.NotEqual(t, nil, err)
+}
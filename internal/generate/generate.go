@@ -1,6 +1,7 @@
 package generate
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/token"
@@ -18,6 +19,13 @@ const (
 	recognizedTypeUnknown recognizedType = iota
 	recognizedTypeContext
 	recognizedTypeError
+	recognizedTypeHTTPRequest
+
+	// results traced until they are consumed in the streaming mode
+	recognizedTypeReader
+	recognizedTypeReadCloser
+	recognizedTypeRecvChan
+	recognizedTypeSeq
 
 	// only for generating
 	recognizedTypeSpan
@@ -30,19 +38,48 @@ type tupleTypePkg struct {
 	end   int
 }
 
+// typeParamRef is an occurrence of a type parameter in a type string
+type typeParamRef struct {
+	name  string
+	begin int
+	end   int
+}
+
 type tupleType struct {
 	name       string
 	typeStr    string
 	recognized recognizedType
 	isVariadic bool
 
-	pkgList []tupleTypePkg
+	pkgList       []tupleTypePkg
+	typeParamRefs []typeParamRef
+
+	signature *signatureType // params and results of a func literal type, nil for other types
+}
+
+// signatureType is the signature of a func literal type, e.g. func(ctx context.Context, msg Msg) error
+type signatureType struct {
+	params  []tupleType
+	results []tupleType
 }
 
 type methodType struct {
 	name    string
 	params  []tupleType
 	results []tupleType
+
+	attributes       []spanAttribute
+	resultAttributes []spanAttribute // recorded after calling the wrapped method
+	location         codeLocation    // only captured when code location attributes are requested
+	spanKind         string          // from the kind directive of the method, empty if not specified
+	skipped          bool            // not traced because of the skip directive or the skipped methods option
+	directives       []directive     // all directives of the method, for custom templates
+}
+
+// codeLocation is the position of a method declaration
+type codeLocation struct {
+	filePath string // package path joined with the file name
+	line     int
 }
 
 type importInfo struct {
@@ -53,6 +90,13 @@ type importInfo struct {
 type interfaceInfo struct {
 	name    string
 	methods []methodType
+
+	pkg        importInfo  // package declaring the interface, empty if it is the package of packageTypeInfo
+	structName string      // struct type of an interface synthesized from the methods of the struct
+	funcType   bool        // a named func type, wrapped by a function instead of a struct
+	typeParams []tupleType // for generic interfaces
+	typeArgs   []tupleType // for an instantiation of a generic interface
+	spanKind   string      // from the kind directive of the interface, empty if not specified
 }
 
 type packageTypeInfo struct {
@@ -61,20 +105,60 @@ type packageTypeInfo struct {
 
 	imports    []importInfo
 	interfaces []interfaceInfo
+	sentinels  []sentinelError // errors ignored by the classifiers of the wrappers
+}
+
+// interfacePkgPath returns the path of the package declaring the interface
+func (info packageTypeInfo) interfacePkgPath(interfaceDetail interfaceInfo) string {
+	if interfaceDetail.pkg.path != "" {
+		return interfaceDetail.pkg.path
+	}
+	return info.path
+}
+
+var ioRecognizedTypes = map[string]recognizedType{
+	"Reader":     recognizedTypeReader,
+	"ReadCloser": recognizedTypeReadCloser,
 }
 
 func getRecognizedType(field *ast.Field, info *types.Info) recognizedType {
-	fieldType := info.TypeOf(field.Type)
-	namedType, ok := fieldType.(*types.Named)
-	if ok {
-		name := namedType.Obj().Name()
-		pkg := namedType.Obj().Pkg()
-		if name == "Context" && pkg != nil && pkg.Path() == "context" {
-			return recognizedTypeContext
+	return recognizedTypeOf(info.TypeOf(field.Type))
+}
+
+// isObjectOf returns true if the object is declared in the package with the path, with the name
+func isObjectOf(obj types.Object, pkgPath string, name string) bool {
+	return obj.Name() == name && obj.Pkg() != nil && obj.Pkg().Path() == pkgPath
+}
+
+func recognizedNamedType(namedType *types.Named) recognizedType {
+	obj := namedType.Obj()
+	switch {
+	case isObjectOf(obj, "context", "Context"):
+		return recognizedTypeContext
+	case obj.Name() == "error" && obj.Pkg() == nil:
+		return recognizedTypeError
+	case obj.Pkg() != nil && obj.Pkg().Path() == "io":
+		return ioRecognizedTypes[obj.Name()]
+	case isObjectOf(obj, "iter", "Seq"):
+		return recognizedTypeSeq
+	default:
+		return recognizedTypeUnknown
+	}
+}
+
+func recognizedTypeOf(fieldType types.Type) recognizedType {
+	switch t := fieldType.(type) {
+	case *types.Pointer:
+		namedType, ok := t.Elem().(*types.Named)
+		if ok && isObjectOf(namedType.Obj(), "net/http", "Request") {
+			return recognizedTypeHTTPRequest
 		}
-		if name == "error" && pkg == nil {
-			return recognizedTypeError
+	case *types.Chan:
+		if t.Dir() == types.RecvOnly {
+			return recognizedTypeRecvChan
 		}
+	case *types.Named:
+		return recognizedNamedType(t)
 	}
 	return recognizedTypeUnknown
 }
@@ -83,13 +167,23 @@ type tupleVisitor struct {
 	begin token.Pos
 	info  *types.Info
 
-	pkgList []tupleTypePkg
+	pkgList       []tupleTypePkg
+	typeParamRefs []typeParamRef
 
 	packageBegin int
 	packageEnd   int
 	foundPkg     bool
 }
 
+func isTypeParamObject(object types.Object) bool {
+	typeName, ok := object.(*types.TypeName)
+	if !ok {
+		return false
+	}
+	_, ok = typeName.Type().(*types.TypeParam)
+	return ok
+}
+
 func (v *tupleVisitor) Visit(node ast.Node) ast.Visitor {
 	ident, ok := node.(*ast.Ident)
 	if !ok {
@@ -99,6 +193,15 @@ func (v *tupleVisitor) Visit(node ast.Node) ast.Visitor {
 	if !ok {
 		return v
 	}
+	if isTypeParamObject(object) {
+		v.typeParamRefs = append(v.typeParamRefs, typeParamRef{
+			name:  ident.Name,
+			begin: int(ident.Pos() - v.begin),
+			end:   int(ident.End() - v.begin),
+		})
+		return v
+	}
+
 	_, ok = object.(*types.PkgName)
 	if ok {
 		v.packageBegin = int(ident.Pos() - v.begin)
@@ -165,7 +268,14 @@ func fieldListToTupleList(
 			recognized: recognized,
 			isVariadic: isVariadic,
 
-			pkgList: visitor.pkgList,
+			pkgList:       visitor.pkgList,
+			typeParamRefs: visitor.typeParamRefs,
+		}
+		if funcType, ok := field.Type.(*ast.FuncType); ok {
+			tupleTemplate.signature = &signatureType{
+				params:  fieldListToTupleList(funcType.Params, fset, fileMap, info),
+				results: fieldListToTupleList(funcType.Results, fset, fileMap, info),
+			}
 		}
 
 		for _, resultName := range field.Names {
@@ -217,6 +327,34 @@ func findInterfaceTypeForDecl(interfaceName string, syntax *ast.File) *ast.TypeS
 	return nil
 }
 
+// typeSpecDocs returns the doc comments of a type spec,
+// including the doc of its declaration if the declaration has only one spec
+func typeSpecDocs(genDecl *ast.GenDecl, typeSpec *ast.TypeSpec) []*ast.CommentGroup {
+	docs := []*ast.CommentGroup{typeSpec.Doc}
+	if len(genDecl.Specs) == 1 {
+		docs = append(docs, genDecl.Doc)
+	}
+	return docs
+}
+
+func findTypeSpecDocs(typeName string, syntaxFiles []*ast.File) []*ast.CommentGroup {
+	for _, syntax := range syntaxFiles {
+		for _, decl := range syntax.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if ok && typeSpec.Name.Name == typeName {
+					return typeSpecDocs(genDecl, typeSpec)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 func findInterfaceTypeSpec(
 	interfaceName string, syntaxFiles []*ast.File,
 ) *ast.TypeSpec {
@@ -327,7 +465,7 @@ func getEmbeddedInterfaceForTypeExpr(typeExpr ast.Expr, foundPkg *packages.Packa
 			return embeddedInterface{}, false
 		}
 		object, ok := foundPkg.TypesInfo.Uses[ident]
-		if !ok {
+		if !ok || object.Pkg() == nil {
 			return embeddedInterface{}, false
 		}
 		return embeddedInterface{
@@ -360,6 +498,14 @@ func getEmbeddedInterfaceForTypeExpr(typeExpr ast.Expr, foundPkg *packages.Packa
 func checkAndFindPackageForInterfaces(
 	pkgList []*packages.Package, interfaceNames ...string,
 ) (*packages.Package, error) {
+	if len(interfaceNames) == 0 {
+		// all interfaces are selected by patterns, in the first package
+		if len(pkgList) == 0 {
+			return nil, &Error{Stage: StageLoad, Err: errors.New("no package matches the pattern")}
+		}
+		return pkgList[0], nil
+	}
+
 	var foundPkg *packages.Package
 	for _, pkg := range pkgList {
 		if pkg.Types.Scope().Lookup(interfaceNames[0]) != nil {
@@ -368,12 +514,12 @@ func checkAndFindPackageForInterfaces(
 		}
 	}
 	if foundPkg == nil {
-		return nil, fmt.Errorf("can not find interface '%s'", interfaceNames[0])
+		return nil, newInterfaceNotFoundError(interfaceNames[0])
 	}
 
 	for _, interfaceName := range interfaceNames[1:] {
 		if foundPkg.Types.Scope().Lookup(interfaceName) == nil {
-			return nil, fmt.Errorf("can not find interface '%s'", interfaceName)
+			return nil, newInterfaceNotFoundError(interfaceName)
 		}
 	}
 	return foundPkg, nil
@@ -404,10 +550,52 @@ func sortImportInfos(imports []importInfo) []importInfo {
 }
 
 func loadPackageTypeData(pattern string, interfaceNames ...string) (packageTypeInfo, error) {
+	return loadPackageTypeDataWithConfig(pattern, interfaceNames, generateConfig{})
+}
+
+func loadPackageTypeDataWithConfig(
+	pattern string, interfaceNames []string, conf generateConfig,
+) (packageTypeInfo, error) {
 	loaded := loadedPackages{}
-	foundPkg, err := loaded.loadPackageForInterfaces(pattern, interfaceNames...)
+	foundPkg, interfaceNames, err := loaded.loadSelectedInterfaces(pattern, interfaceNames, conf, true)
+	if err != nil {
+		return packageTypeInfo{}, err
+	}
+	return loaded.packageTypeData(foundPkg, interfaceNames, conf)
+}
+
+// loadSelectedInterfaces loads the package, expanding the glob patterns of the interface names
+// and selecting the interfaces matching the match expression if withMatch is true
+//
+//revive:disable-next-line:flag-parameter
+func (loaded loadedPackages) loadSelectedInterfaces(
+	pattern string, interfaceNames []string, conf generateConfig, withMatch bool,
+) (loadedPackage, []string, error) {
+	selection, err := newInterfaceSelection(conf)
+	if err != nil {
+		return loadedPackage{}, nil, err
+	}
+
+	foundPkg, err := loaded.loadPackageForInterfaces(pattern, explicitInterfaceNames(interfaceNames)...)
+	if err != nil {
+		return loadedPackage{}, nil, err
+	}
+
+	interfaceNames, selected, err := selection.selectInterfaces(foundPkg.pkg.Types.Scope(), interfaceNames, withMatch)
+	if err != nil {
+		return loadedPackage{}, nil, err
+	}
+	if len(selected) > 0 && conf.matchReport != nil {
+		_, _ = fmt.Fprintf(conf.matchReport, "%s: matched %s\n", foundPkg.pkg.PkgPath, strings.Join(selected, ", "))
+	}
+	return foundPkg, interfaceNames, nil
+}
+
+func (loaded loadedPackages) packageTypeData(
+	foundPkg loadedPackage, interfaceNames []string, conf generateConfig,
+) (packageTypeInfo, error) {
+	sentinels, err := loadSentinelErrors(conf.ignoreErrors)
 	if err != nil {
-		fmt.Println("loadPackageForInterfaces", err)
 		return packageTypeInfo{}, err
 	}
 
@@ -416,11 +604,12 @@ func loadPackageTypeData(pattern string, interfaceNames ...string) (packageTypeI
 	var interfaces []interfaceInfo
 	for _, interfaceName := range interfaceNames {
 		finder := newInterfaceInfoFinder(loaded, visitorData)
+		finder.typeArgs = conf.typeArgs[interfaceName]
+		finder.withLocation = conf.codeLocation
 
 		info, err := finder.getInterfaceInfo(interfaceName, foundPkg)
 		if err != nil {
-			fmt.Println("getInterfaceInfo", err)
-			return packageTypeInfo{}, err
+			return packageTypeInfo{}, WrapError(err, StageFind, interfaceName)
 		}
 		interfaces = append(interfaces, info)
 	}
@@ -430,5 +619,97 @@ func loadPackageTypeData(pattern string, interfaceNames ...string) (packageTypeI
 		path:       foundPkg.pkg.PkgPath,
 		imports:    sortImportInfos(visitorData.imports),
 		interfaces: interfaces,
+		sentinels:  sentinels,
 	}, nil
 }
+
+// markSkippedMethods marks the methods with the names of the form Method or Interface.Method as skipped,
+// names not matching any method are ignored, e.g. for scan mode with interfaces of different packages
+func markSkippedMethods(info packageTypeInfo, skipMethods []string) packageTypeInfo {
+	if len(skipMethods) == 0 {
+		return info
+	}
+
+	skipped := map[string]bool{}
+	for _, name := range skipMethods {
+		skipped[name] = true
+	}
+
+	interfaces := make([]interfaceInfo, 0, len(info.interfaces))
+	for _, interfaceDetail := range info.interfaces {
+		if !interfaceDetail.funcType {
+			methods := make([]methodType, 0, len(interfaceDetail.methods))
+			for _, method := range interfaceDetail.methods {
+				if skipped[method.name] || skipped[interfaceDetail.name+"."+method.name] {
+					method.skipped = true
+				}
+				methods = append(methods, method)
+			}
+			interfaceDetail.methods = methods
+		}
+		interfaces = append(interfaces, interfaceDetail)
+	}
+	info.interfaces = interfaces
+	return info
+}
+
+// InterfaceGroup is a list of interfaces of a package
+type InterfaceGroup struct {
+	Pattern        string // pattern for loading the package, e.g. . or github.com/acme/lib/store
+	InterfaceNames []string
+}
+
+// declareInterfaces adds the package path of the interfaces to declared,
+// failing if an interface with the same name is declared in another package
+func declareInterfaces(declared map[string]string, info packageTypeInfo) error {
+	for _, interfaceDetail := range info.interfaces {
+		if existing, ok := declared[interfaceDetail.name]; ok && existing != info.path {
+			return &Error{
+				Stage:     StageFind,
+				Interface: interfaceDetail.name,
+				Err: fmt.Errorf("interface '%s' is declared in both '%s' and '%s'",
+					interfaceDetail.name, existing, info.path),
+			}
+		}
+		declared[interfaceDetail.name] = info.path
+	}
+	return nil
+}
+
+// loadPackageTypeDataForGroups merges the interfaces of the groups into the data of the package of the first group
+func loadPackageTypeDataForGroups(groups []InterfaceGroup, conf generateConfig) (packageTypeInfo, error) {
+	loaded := loadedPackages{}
+
+	var result packageTypeInfo
+	declared := map[string]string{} // package paths by interface names
+	for i, group := range groups {
+		foundPkg, interfaceNames, err := loaded.loadSelectedInterfaces(
+			group.Pattern, group.InterfaceNames, conf, i == 0,
+		)
+		if err != nil {
+			return packageTypeInfo{}, err
+		}
+		info, err := loaded.packageTypeData(foundPkg, interfaceNames, conf)
+		if err != nil {
+			return packageTypeInfo{}, err
+		}
+
+		if err := declareInterfaces(declared, info); err != nil {
+			return packageTypeInfo{}, err
+		}
+
+		if i == 0 {
+			result = info
+			continue
+		}
+		for _, interfaceDetail := range info.interfaces {
+			interfaceDetail.pkg = importInfo{
+				name: info.name,
+				path: info.path,
+			}
+			result.interfaces = append(result.interfaces, interfaceDetail)
+		}
+		result.imports = sortImportInfos(append(result.imports, info.imports...))
+	}
+	return result, nil
+}
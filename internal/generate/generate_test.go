@@ -3,6 +3,7 @@ package generate
 import (
 	"errors"
 	"github.com/stretchr/testify/assert"
+	"path/filepath"
 	"testing"
 )
 
@@ -504,20 +505,182 @@ func TestLoadPackageTypeInfo_For_Type_Alias(t *testing.T) {
 
 func TestLoadPackageTypeInfo_Not_Found(t *testing.T) {
 	info, err := loadPackageTypeData("./hello", "RandomInterface")
-	assert.Equal(t, errors.New("can not find interface 'RandomInterface'"), err)
+	assert.Equal(t, &Error{
+		Stage:     StageFind,
+		Interface: "RandomInterface",
+		Err:       errors.New("can not find interface 'RandomInterface'"),
+	}, err)
+	assert.Equal(t, "find interface 'RandomInterface': can not find interface 'RandomInterface'", err.Error())
 	assert.Equal(t, packageTypeInfo{}, info)
 }
 
 func TestLoadPackageTypeInfo_Not_Found_Second_Interface(t *testing.T) {
 	info, err := loadPackageTypeData("./hello", "Simple", "AnotherInterface")
-	assert.Equal(t, errors.New("can not find interface 'AnotherInterface'"), err)
+	assert.Equal(t, &Error{
+		Stage:     StageFind,
+		Interface: "AnotherInterface",
+		Err:       errors.New("can not find interface 'AnotherInterface'"),
+	}, err)
 	assert.Equal(t, packageTypeInfo{}, info)
 }
 
 func TestLoadPackageTypeInfo_Not_An_Interface(t *testing.T) {
+	info, err := loadPackageTypeData("./hello", "UserID")
+	assert.Equal(t, packageTypeInfo{}, info)
+
+	var genErr *Error
+	assert.True(t, errors.As(err, &genErr))
+	assert.Equal(t, StageFind, genErr.Stage)
+	assert.Equal(t, "UserID", genErr.Interface)
+	assert.Equal(t, "hello.go", filepath.Base(genErr.Position.Filename))
+	assert.Equal(t, 74, genErr.Position.Line)
+	assert.Equal(t, errors.New("name 'UserID' is not an interface"), genErr.Err)
+}
+
+func TestLoadPackageTypeInfo_Struct_Without_Exported_Methods(t *testing.T) {
 	info, err := loadPackageTypeData("./hello", "User")
-	assert.Equal(t, errors.New("name 'User' is not an interface"), err)
 	assert.Equal(t, packageTypeInfo{}, info)
+
+	var genErr *Error
+	assert.True(t, errors.As(err, &genErr))
+	assert.Equal(t, StageFind, genErr.Stage)
+	assert.Equal(t, "User", genErr.Interface)
+	assert.Equal(t, 14, genErr.Position.Line)
+	assert.Equal(t, errors.New("struct type 'User' has no exported methods"), genErr.Err)
+}
+
+func TestLoadPackageTypeInfo_Struct(t *testing.T) {
+	info, err := loadPackageTypeData("./hello", "Service")
+	assert.Equal(t, nil, err)
+
+	helloPath := rootPackagePath + "/hello"
+	sdkPath := rootPackagePath + "/hello/otel/sdk"
+	otelPath := rootPackagePath + "/hello/otel"
+
+	assert.Equal(t, []importInfo{
+		{
+			name: "context",
+			path: "context",
+		},
+		{
+			name: "otelgo",
+			path: otelPath,
+		},
+		{
+			name: "otelgo",
+			path: sdkPath,
+		},
+	}, info.imports)
+
+	assert.Equal(t, interfaceInfo{
+		name:       "ServiceAPI",
+		structName: "Service",
+		methods: []methodType{
+			{
+				name: "Close",
+			},
+			{
+				name: "Compute",
+				params: []tupleType{
+					{
+						name:       "ctx",
+						typeStr:    "context.Context",
+						recognized: recognizedTypeContext,
+						pkgList:    pkgListContext(),
+					},
+					{
+						name:    "x",
+						typeStr: "string",
+					},
+				},
+				results: []tupleType{
+					{
+						typeStr:    "error",
+						recognized: recognizedTypeError,
+					},
+				},
+			},
+			{
+				name: "GetUser",
+				params: []tupleType{
+					{
+						name:       "ctx",
+						typeStr:    "context.Context",
+						recognized: recognizedTypeContext,
+						pkgList:    pkgListContext(),
+					},
+					{
+						name:    "id",
+						typeStr: "int64",
+					},
+				},
+				results: []tupleType{
+					{
+						typeStr: "*User",
+						pkgList: []tupleTypePkg{
+							{
+								path:  helloPath,
+								begin: 1,
+								end:   1,
+							},
+						},
+					},
+					{
+						typeStr:    "error",
+						recognized: recognizedTypeError,
+					},
+				},
+			},
+			{
+				name: "ListUsers",
+				params: []tupleType{
+					{
+						name:       "ctx",
+						typeStr:    "context.Context",
+						recognized: recognizedTypeContext,
+						pkgList:    pkgListContext(),
+					},
+					{
+						name:    "contents",
+						typeStr: "map[otelgo.Content]otelgo.Person",
+						pkgList: []tupleTypePkg{
+							{
+								path:  sdkPath,
+								begin: len("map["),
+								end:   len("map[otelgo"),
+							},
+							{
+								path:  otelPath,
+								begin: len("map[otelgo.Content]"),
+								end:   len("map[otelgo.Content]otelgo"),
+							},
+						},
+					},
+					{
+						name:       "ids",
+						typeStr:    "...int64",
+						isVariadic: true,
+					},
+				},
+				results: []tupleType{
+					{
+						typeStr: "[]User",
+						pkgList: []tupleTypePkg{
+							{
+								path:  helloPath,
+								begin: 2,
+								end:   2,
+							},
+						},
+					},
+					{
+						typeStr:    "error",
+						recognized: recognizedTypeError,
+					},
+				},
+			},
+		},
+	}, info.interfaces[0])
 }
 
 func TestLoadPackageTypeInfo_Interface_With_Underscore(t *testing.T) {
@@ -696,3 +859,563 @@ func TestLoadPackageTypeInfo_For_InterfaceType_With_Generic_Params_And_Returns(t
 		interfaces: []interfaceInfo{interface1},
 	}, info)
 }
+
+func TestLoadPackageTypeInfo_With_Attribute_Directives(t *testing.T) {
+	info, err := loadPackageTypeData("./hello", "AttributeHandler")
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, 1, len(info.interfaces))
+	assert.Equal(t, []spanAttribute{
+		{
+			key:         "user.id",
+			index:       1,
+			constructor: "Int64",
+			conversion:  "int64",
+		},
+		{
+			key:         "user.name",
+			index:       2,
+			constructor: "String",
+		},
+		{
+			key:         "user.active",
+			index:       3,
+			constructor: "Bool",
+		},
+		{
+			key:         "timeout",
+			index:       4,
+			constructor: "Stringer",
+		},
+	}, info.interfaces[0].methods[0].attributes)
+}
+
+func TestLoadPackageTypeInfo_With_Attribute_Directives_Unsupported_Type(t *testing.T) {
+	info, err := loadPackageTypeData("./hello", "InvalidAttributeHandler")
+	assert.Equal(t, packageTypeInfo{}, info)
+
+	var genErr *Error
+	assert.True(t, errors.As(err, &genErr))
+	assert.Equal(t, StageFind, genErr.Stage)
+	assert.Equal(t, "InvalidAttributeHandler", genErr.Interface)
+	assert.Equal(t, 85, genErr.Position.Line)
+	assert.Equal(t, "unsupported type '*User' of param 'u' for attribute", genErr.Err.Error())
+}
+
+func TestLoadPackageTypeInfo_Generic_Interface(t *testing.T) {
+	info, err := loadPackageTypeData("./hello", "GenericRepo")
+	assert.Equal(t, nil, err)
+
+	sdkPath := rootPackagePath + "/hello/otel/sdk"
+
+	assert.Equal(t, packageTypeInfo{
+		name: "hello",
+		path: rootPackagePath + "/hello",
+		imports: []importInfo{
+			{
+				name: "context",
+				path: "context",
+			},
+			{
+				name: "otelgo",
+				path: sdkPath,
+			},
+		},
+		interfaces: []interfaceInfo{
+			{
+				name: "GenericRepo",
+				methods: []methodType{
+					{
+						name: "Get",
+						params: []tupleType{
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+							{
+								name:    "key",
+								typeStr: "K",
+								typeParamRefs: []typeParamRef{
+									{name: "K", begin: 0, end: 1},
+								},
+							},
+						},
+						results: []tupleType{
+							{
+								typeStr: "T",
+								typeParamRefs: []typeParamRef{
+									{name: "T", begin: 0, end: 1},
+								},
+							},
+							{
+								typeStr:    "error",
+								recognized: recognizedTypeError,
+							},
+						},
+					},
+					{
+						name: "List",
+						params: []tupleType{
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+							{
+								name:       "keys",
+								typeStr:    "...K",
+								isVariadic: true,
+								typeParamRefs: []typeParamRef{
+									{name: "K", begin: 3, end: 4},
+								},
+							},
+						},
+						results: []tupleType{
+							{
+								typeStr: "[]Null[T]",
+								pkgList: []tupleTypePkg{
+									{
+										path:  rootPackagePath + "/hello",
+										begin: len("[]"),
+										end:   len("[]"),
+									},
+								},
+								typeParamRefs: []typeParamRef{
+									{name: "T", begin: len("[]Null["), end: len("[]Null[T")},
+								},
+							},
+							{
+								typeStr:    "error",
+								recognized: recognizedTypeError,
+							},
+						},
+					},
+				},
+				typeParams: []tupleType{
+					{
+						name:    "T",
+						typeStr: "any",
+					},
+					{
+						name:    "K",
+						typeStr: "otelgosdk.Keyer",
+						pkgList: []tupleTypePkg{
+							{
+								path: sdkPath,
+								end:  len("otelgosdk"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}, info)
+}
+
+func TestLoadPackageTypeInfo_Generic_Interface_With_Type_Args(t *testing.T) {
+	info, err := loadPackageTypeDataWithConfig("./hello", []string{"GenericRepo"},
+		computeGenerateConfig(WithTypeArgs("GenericRepo", "*User", "otelgosdk.Content")),
+	)
+	assert.Equal(t, nil, err)
+
+	sdkPath := rootPackagePath + "/hello/otel/sdk"
+
+	assert.Equal(t, []importInfo{
+		{
+			name: "context",
+			path: "context",
+		},
+		{
+			name: "otelgo",
+			path: sdkPath,
+		},
+	}, info.imports)
+
+	interfaceDetail := info.interfaces[0]
+	assert.Equal(t, []tupleType(nil), interfaceDetail.typeParams)
+	assert.Equal(t, []tupleType{
+		{
+			typeStr: "*User",
+			pkgList: []tupleTypePkg{
+				{
+					path:  rootPackagePath + "/hello",
+					begin: 1,
+					end:   1,
+				},
+			},
+		},
+		{
+			typeStr: "otelgosdk.Content",
+			pkgList: []tupleTypePkg{
+				{
+					path: sdkPath,
+					end:  len("otelgosdk"),
+				},
+			},
+		},
+	}, interfaceDetail.typeArgs)
+
+	assert.Equal(t, tupleType{
+		name:    "key",
+		typeStr: "otelgosdk.Content",
+		pkgList: []tupleTypePkg{
+			{
+				path: sdkPath,
+				end:  len("otelgosdk"),
+			},
+		},
+	}, interfaceDetail.methods[0].params[1])
+
+	assert.Equal(t, tupleType{
+		typeStr: "[]Null[*User]",
+		pkgList: []tupleTypePkg{
+			{
+				path:  rootPackagePath + "/hello",
+				begin: len("[]"),
+				end:   len("[]"),
+			},
+			{
+				path:  rootPackagePath + "/hello",
+				begin: len("[]Null[*"),
+				end:   len("[]Null[*"),
+			},
+		},
+	}, interfaceDetail.methods[1].results[0])
+}
+
+func TestLoadPackageTypeInfo_Generic_Interface_With_Invalid_Type_Args(t *testing.T) {
+	_, err := loadPackageTypeDataWithConfig("./hello", []string{"GenericRepo"},
+		computeGenerateConfig(WithTypeArgs("GenericRepo", "*User")),
+	)
+	assert.Equal(t, &Error{
+		Stage:     StageFind,
+		Interface: "GenericRepo",
+		Err:       errors.New("interface 'GenericRepo' expects 2 type arguments, got 1"),
+	}, err)
+
+	_, err = loadPackageTypeDataWithConfig("./hello", []string{"GenericRepo"},
+		computeGenerateConfig(WithTypeArgs("GenericRepo", "*User", "int")),
+	)
+	assert.Error(t, err)
+
+	_, err = loadPackageTypeDataWithConfig("./hello", []string{"Simple"},
+		computeGenerateConfig(WithTypeArgs("Simple", "int")),
+	)
+	assert.Equal(t, &Error{
+		Stage:     StageFind,
+		Interface: "Simple",
+		Err:       errors.New("interface 'Simple' is not generic"),
+	}, err)
+}
+
+func TestLoadPackageTypeInfo_With_Ignore_Errors(t *testing.T) {
+	info, err := loadPackageTypeDataWithConfig("./hello", []string{"Simple"},
+		computeGenerateConfig(WithIgnoreErrors("database/sql.ErrNoRows", rootPackagePath+"/hello.ErrUserNotFound")),
+	)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []sentinelError{
+		{
+			pkg: importInfo{
+				name: "sql",
+				path: "database/sql",
+			},
+			name: "ErrNoRows",
+		},
+		{
+			pkg: importInfo{
+				name: "hello",
+				path: rootPackagePath + "/hello",
+			},
+			name: "ErrUserNotFound",
+		},
+	}, info.sentinels)
+}
+
+func TestLoadPackageTypeInfo_With_Invalid_Ignore_Errors(t *testing.T) {
+	_, err := loadPackageTypeDataWithConfig("./hello", []string{"Simple"},
+		computeGenerateConfig(WithIgnoreErrors("ErrNoRows")),
+	)
+	assert.Equal(t, &Error{
+		Stage: StageArgs,
+		Err:   errors.New("invalid sentinel error 'ErrNoRows', expected import/path.Name"),
+	}, err)
+
+	_, err = loadPackageTypeDataWithConfig("./hello", []string{"Simple"},
+		computeGenerateConfig(WithIgnoreErrors("database/sql.ErrTxDone", "database/sql.DB")),
+	)
+	assert.Equal(t, &Error{
+		Stage: StageLoad,
+		Err:   errors.New("'database/sql.DB' is not an exported error variable"),
+	}, err)
+}
+
+func TestLoadPackageTypeInfo_With_Code_Location(t *testing.T) {
+	info, err := loadPackageTypeDataWithConfig("./hello", []string{"Simple"},
+		computeGenerateConfig(WithCodeLocation()),
+	)
+	assert.Equal(t, nil, err)
+
+	var locations []codeLocation
+	for _, method := range info.interfaces[0].methods {
+		locations = append(locations, method.location)
+	}
+	assert.Equal(t, []codeLocation{
+		{filePath: rootPackagePath + "/hello/embed/parser.go", line: 15},
+		{filePath: rootPackagePath + "/hello/embed/parser.go", line: 16},
+		{filePath: rootPackagePath + "/hello/embed/parser.go", line: 17},
+		{filePath: rootPackagePath + "/hello/hello.go", line: 45},
+		{filePath: rootPackagePath + "/hello/hello.go", line: 46},
+	}, locations)
+}
+
+func TestLoadPackageTypeInfo_With_Span_Kind_Directives(t *testing.T) {
+	info, err := loadPackageTypeData("./hello", "KindHandler")
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, "client", info.interfaces[0].spanKind)
+	assert.Equal(t, "", info.interfaces[0].methods[0].spanKind)
+	assert.Equal(t, "producer", info.interfaces[0].methods[1].spanKind)
+}
+
+func TestLoadPackageTypeInfo_With_Invalid_Span_Kind(t *testing.T) {
+	_, err := loadPackageTypeData("./hello", "InvalidKindHandler")
+
+	var genErr *Error
+	assert.True(t, errors.As(err, &genErr))
+	assert.Equal(t, "InvalidKindHandler", genErr.Interface)
+	assert.Equal(t, 110, genErr.Position.Line)
+	assert.Equal(t,
+		"invalid span kind 'remote', expected internal, server, client, producer or consumer",
+		genErr.Err.Error())
+}
+
+func TestLoadPackageTypeInfo_HTTP_Request_Param(t *testing.T) {
+	info, err := loadPackageTypeData("./hello", "MixedContextHandler")
+	assert.Equal(t, nil, err)
+
+	methods := info.interfaces[0].methods
+	assert.Equal(t, recognizedTypeContext, methods[0].params[1].recognized)
+	assert.Equal(t, recognizedTypeUnknown, methods[1].params[0].recognized)
+	assert.Equal(t, recognizedTypeHTTPRequest, methods[1].params[1].recognized)
+}
+
+func TestLoadPackageTypeInfo_Func_Type(t *testing.T) {
+	info, err := loadPackageTypeData("./hello", "HandlerFunc")
+	assert.Equal(t, nil, err)
+
+	userPkgList := []tupleTypePkg{
+		{
+			path:  rootPackagePath + "/hello",
+			begin: 1,
+			end:   1,
+		},
+	}
+
+	assert.Equal(t, []importInfo{
+		{
+			name: "context",
+			path: "context",
+		},
+	}, info.imports)
+	assert.Equal(t, interfaceInfo{
+		name:     "HandlerFunc",
+		funcType: true,
+		methods: []methodType{
+			{
+				name: "HandlerFunc",
+				params: []tupleType{
+					{
+						name:       "ctx",
+						typeStr:    "context.Context",
+						recognized: recognizedTypeContext,
+						pkgList:    pkgListContext(),
+					},
+					{
+						name:    "name",
+						typeStr: "string",
+					},
+					{
+						name:    "f",
+						typeStr: "*User",
+						pkgList: userPkgList,
+					},
+				},
+				results: []tupleType{
+					{
+						typeStr: "*User",
+						pkgList: userPkgList,
+					},
+					{
+						typeStr:    "error",
+						recognized: recognizedTypeError,
+					},
+				},
+			},
+		},
+	}, info.interfaces[0])
+}
+
+func TestLoadPackageTypeInfo_Generic_Func_Type(t *testing.T) {
+	info, err := loadPackageTypeData("./hello", "ListFunc")
+	assert.Equal(t, nil, err)
+
+	interfaceDetail := info.interfaces[0]
+	assert.Equal(t, true, interfaceDetail.funcType)
+	assert.Equal(t, []tupleType{
+		{
+			name:    "T",
+			typeStr: "any",
+		},
+	}, interfaceDetail.typeParams)
+	assert.Equal(t, "[]T", interfaceDetail.methods[0].results[0].typeStr)
+}
+
+func TestLoadPackageTypeInfo_With_Result_Directives(t *testing.T) {
+	info, err := loadPackageTypeData("./hello", "ResultHandler")
+	assert.Equal(t, nil, err)
+
+	methods := info.interfaces[0].methods
+	assert.Equal(t, []spanAttribute{
+		{
+			key:         "db.rows_affected",
+			index:       0,
+			constructor: "Int64",
+		},
+	}, methods[0].resultAttributes)
+	assert.Equal(t, []spanAttribute{
+		{
+			key:         "result.count",
+			index:       0,
+			constructor: "Int",
+			length:      true,
+		},
+		{
+			key:         "cache.hit",
+			index:       1,
+			constructor: "Bool",
+		},
+	}, methods[1].resultAttributes)
+	assert.Equal(t, []spanAttribute{
+		{
+			key:         "result.count",
+			index:       0,
+			constructor: "Int",
+			length:      true,
+		},
+	}, methods[2].resultAttributes)
+	assert.Equal(t, []spanAttribute(nil), methods[0].attributes)
+}
+
+func TestLoadPackageTypeInfo_With_Result_Directives_Unsupported_Type(t *testing.T) {
+	_, err := loadPackageTypeData("./hello", "InvalidResultHandler")
+
+	var genErr *Error
+	assert.True(t, errors.As(err, &genErr))
+	assert.Equal(t, "InvalidResultHandler", genErr.Interface)
+	assert.Equal(t, 168, genErr.Position.Line)
+	assert.Equal(t, "unsupported type '*User' of result 'u' for attribute", genErr.Err.Error())
+}
+
+func TestLoadPackageTypeInfo_With_Result_Directives_Nil_Stringer(t *testing.T) {
+	_, err := loadPackageTypeData("./hello", "InvalidStringerHandler")
+
+	var genErr *Error
+	assert.True(t, errors.As(err, &genErr))
+	assert.Equal(t, "InvalidStringerHandler", genErr.Interface)
+	assert.Equal(t, 10, genErr.Position.Line)
+	assert.Equal(t, "unsupported type 'fmt.Stringer' of result 's' for attribute", genErr.Err.Error())
+}
+
+func TestLoadPackageTypeInfo_With_Stream_Results(t *testing.T) {
+	info, err := loadPackageTypeData("./hello", "StreamHandler")
+	assert.Equal(t, nil, err)
+
+	var recognized []recognizedType
+	for _, method := range info.interfaces[0].methods {
+		recognized = append(recognized, method.results[0].recognized)
+	}
+	assert.Equal(t, []recognizedType{
+		recognizedTypeReadCloser,
+		recognizedTypeReader,
+		recognizedTypeRecvChan,
+		recognizedTypeSeq,
+		recognizedTypeUnknown,
+	}, recognized)
+}
+
+func TestLoadPackageTypeInfo_With_Func_Literal_Params(t *testing.T) {
+	info, err := loadPackageTypeData("./hello", "Subscriber")
+	assert.Equal(t, nil, err)
+
+	methods := info.interfaces[0].methods
+	assert.Equal(t, &signatureType{
+		params: []tupleType{
+			{
+				name:       "ctx",
+				typeStr:    "context.Context",
+				recognized: recognizedTypeContext,
+				pkgList:    pkgListContext(),
+			},
+			{
+				name:    "u",
+				typeStr: "User",
+				pkgList: []tupleTypePkg{
+					{
+						path:  "github.com/QuangTung97/otelwrap/internal/generate/hello",
+						begin: 0,
+						end:   0,
+					},
+				},
+			},
+		},
+		results: []tupleType{
+			{
+				typeStr:    "error",
+				recognized: recognizedTypeError,
+			},
+		},
+	}, methods[0].params[2].signature)
+
+	assert.Equal(t, &signatureType{}, methods[1].results[0].signature)
+	assert.Equal(t, "stop", methods[1].results[0].name)
+
+	assert.Equal(t, true, methods[2].params[2].isVariadic)
+	assert.Equal(t, (*signatureType)(nil), methods[2].params[2].signature)
+}
+
+func TestLoadPackageTypeInfo_Groups(t *testing.T) {
+	info, err := loadPackageTypeDataForGroups([]InterfaceGroup{
+		{Pattern: "./hello", InterfaceNames: []string{"Simple"}},
+		{Pattern: "./hello/embed", InterfaceNames: []string{"Parser"}},
+	}, generateConfig{})
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, "hello", info.name)
+	assert.Equal(t, 2, len(info.interfaces))
+	assert.Equal(t, "Simple", info.interfaces[0].name)
+	assert.Equal(t, importInfo{}, info.interfaces[0].pkg)
+	assert.Equal(t, "Parser", info.interfaces[1].name)
+	assert.Equal(t, importInfo{
+		name: "embed",
+		path: "github.com/QuangTung97/otelwrap/internal/generate/hello/embed",
+	}, info.interfaces[1].pkg)
+	assert.Equal(t, "github.com/QuangTung97/otelwrap/internal/generate/hello/embed",
+		info.interfacePkgPath(info.interfaces[1]))
+}
+
+func TestLoadPackageTypeInfo_Groups_Duplicated_Interface(t *testing.T) {
+	_, err := loadPackageTypeDataForGroups([]InterfaceGroup{
+		{Pattern: "./hello/another", InterfaceNames: []string{"HandlerAlias"}},
+		{Pattern: "../../otelwrap", InterfaceNames: []string{"HandlerAlias"}},
+	}, generateConfig{})
+	assert.Equal(t, &Error{
+		Stage:     StageFind,
+		Interface: "HandlerAlias",
+		Err: errors.New("interface 'HandlerAlias' is declared in both " +
+			"'github.com/QuangTung97/otelwrap/internal/generate/hello/another' and " +
+			"'github.com/QuangTung97/otelwrap/otelwrap'"),
+	}, err)
+}
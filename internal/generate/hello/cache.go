@@ -0,0 +1,13 @@
+package hello
+
+import "context"
+
+// Cache ...
+type Cache interface {
+	// Get is called in hot paths
+	//
+	//otelwrap:skip
+	Get(ctx context.Context, key string) (User, error)
+	Set(ctx context.Context, key string, u User) error
+	Ping(ctx context.Context) error
+}
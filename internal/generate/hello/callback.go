@@ -0,0 +1,14 @@
+package hello
+
+import (
+	"context"
+	"net/http"
+)
+
+// Subscriber ...
+type Subscriber interface {
+	Subscribe(ctx context.Context, topic string, handler func(ctx context.Context, u User) error) error
+	Start(ctx context.Context) (stop func(), err error)
+	Each(ctx context.Context, fn func(User) bool, filters ...func(User) bool)
+	Handle(w http.ResponseWriter, r *http.Request, next func(context.Context, string))
+}
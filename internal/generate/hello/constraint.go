@@ -0,0 +1,13 @@
+package hello
+
+import "context"
+
+// Number is a constraint interface, it can not be wrapped
+type Number interface {
+	~int | ~int64
+}
+
+// NumberRepo ...
+type NumberRepo interface {
+	Sum(ctx context.Context, values []int64) (int64, error)
+}
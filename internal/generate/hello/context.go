@@ -0,0 +1,13 @@
+package hello
+
+import (
+	"context"
+	"net/http"
+)
+
+// MixedContextHandler ...
+type MixedContextHandler interface {
+	Get(id int64, ctx context.Context) error
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+	Check() (bool, error)
+}
@@ -0,0 +1,15 @@
+package hello
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// ExpiringCache ...
+type ExpiringCache interface {
+	//otelwrap:skip
+	Expire(ctx context.Context, key string, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+	Dump(w io.Writer) error
+}
@@ -3,6 +3,7 @@ package hello
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"github.com/QuangTung97/otelwrap/internal/generate/hello/embed"
 	otelgo "github.com/QuangTung97/otelwrap/internal/generate/hello/otel"
 	otelgosdk "github.com/QuangTung97/otelwrap/internal/generate/hello/otel/sdk"
@@ -68,3 +69,102 @@ type Null[T any] struct {
 type GenericHandler interface {
 	GetNull(ctx context.Context, info Null[otelgo.AnotherInfo]) (Null[otelgo.Person], error)
 }
+
+// UserID ...
+type UserID int64
+
+// AttributeHandler ...
+type AttributeHandler interface {
+	//otelwrap:attr id=user.id name=user.name
+	//otelwrap:attr active=user.active d=timeout
+	GetUser(ctx context.Context, id UserID, name string, active bool, d time.Duration) (User, error)
+}
+
+// InvalidAttributeHandler ...
+type InvalidAttributeHandler interface {
+	//otelwrap:attr u=user
+	Handle(ctx context.Context, u *User) error
+}
+
+// GenericRepo ...
+type GenericRepo[T any, K otelgosdk.Keyer] interface {
+	Get(ctx context.Context, key K) (T, error)
+	List(ctx context.Context, keys ...K) ([]Null[T], error)
+}
+
+// ErrUserNotFound ...
+var ErrUserNotFound = errors.New("user not found")
+
+// KindHandler ...
+//
+//otelwrap:kind client
+type KindHandler interface {
+	Get(ctx context.Context, id int64) error
+
+	//otelwrap:kind producer
+	Publish(ctx context.Context, u *User) error
+}
+
+// InvalidKindHandler ...
+type InvalidKindHandler interface {
+	//otelwrap:kind remote
+	Get(ctx context.Context, id int64) error
+}
+
+// Service ...
+type Service struct {
+	embed.Parser
+}
+
+//revive:disable:unused-parameter,unused-receiver
+
+// GetUser ...
+func (s *Service) GetUser(ctx context.Context, id int64) (*User, error) {
+	return &User{ID: id}, nil
+}
+
+// ListUsers ...
+func (s Service) ListUsers(
+	ctx context.Context,
+	contents map[otelgosdk.Content]otelgo.Person,
+	ids ...int64,
+) ([]User, error) {
+	return nil, nil
+}
+
+//revive:enable:unused-parameter,unused-receiver
+
+// Close ...
+func (s *Service) Close() {
+	s.reset()
+}
+
+func (*Service) reset() {
+}
+
+// HandlerFunc ...
+type HandlerFunc func(ctx context.Context, name string, f *User) (*User, error)
+
+// ListFunc ...
+type ListFunc[T any] func(ctx context.Context, limit int) []T
+
+// NoContextFunc ...
+type NoContextFunc func(n int) error
+
+// ResultHandler ...
+type ResultHandler interface {
+	//otelwrap:result n=db.rows_affected
+	Update(ctx context.Context, id UserID) (n int64, err error)
+
+	//otelwrap:result users=result.count hit=cache.hit
+	List(ctx context.Context) (users []User, hit bool, err error)
+
+	//otelwrap:result users=result.count
+	Get(ctx context.Context) (users map[UserID]User)
+}
+
+// InvalidResultHandler ...
+type InvalidResultHandler interface {
+	//otelwrap:result u=user
+	Get(ctx context.Context) (u *User, err error)
+}
@@ -4,3 +4,13 @@ package otelgo
 type Content struct {
 	Value string
 }
+
+// Key ...
+func (c Content) Key() string {
+	return c.Value
+}
+
+// Keyer ...
+type Keyer interface {
+	Key() string
+}
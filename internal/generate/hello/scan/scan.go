@@ -0,0 +1,36 @@
+package scan
+
+import (
+	"context"
+	"github.com/QuangTung97/otelwrap/internal/generate/hello/embed"
+)
+
+// Repo ...
+//
+//otelwrap:wrap
+type Repo interface {
+	embed.Parser
+
+	Get(ctx context.Context, id int64) error
+}
+
+// Client ...
+//
+//otelwrap:wrap out=client_wrappers.go prefix=Traced
+type Client interface {
+	Call(ctx context.Context) error
+}
+
+type (
+	// Cache ...
+	//
+	//otelwrap:wrap
+	Cache interface {
+		Set(ctx context.Context, key string)
+	}
+
+	// Ignored ...
+	Ignored interface {
+		Run(ctx context.Context)
+	}
+)
@@ -0,0 +1,16 @@
+package hello
+
+import (
+	"context"
+	"io"
+	"iter"
+)
+
+// StreamHandler ...
+type StreamHandler interface {
+	Download(ctx context.Context, key string) (io.ReadCloser, error)
+	Open(ctx context.Context, key string) (io.Reader, error)
+	Watch(ctx context.Context) <-chan User
+	Users(ctx context.Context) iter.Seq[User]
+	Size(ctx context.Context, key string) (int64, error)
+}
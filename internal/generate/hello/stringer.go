@@ -0,0 +1,12 @@
+package hello
+
+import (
+	"context"
+	"fmt"
+)
+
+// InvalidStringerHandler ...
+type InvalidStringerHandler interface {
+	//otelwrap:result s=name
+	Name(ctx context.Context) (s fmt.Stringer, err error)
+}
@@ -3,12 +3,21 @@ package generate
 import (
 	"fmt"
 	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"path/filepath"
+	"strings"
 )
 
 type interfaceInfoFinder struct {
 	methods     []methodType
 	loaded      loadedPackages
 	visitorData *importVisitorData
+
+	typeArgs     []string
+	withLocation bool
 }
 
 func newInterfaceInfoFinder(loaded loadedPackages, visitorData *importVisitorData) *interfaceInfoFinder {
@@ -23,7 +32,8 @@ func (f *interfaceInfoFinder) getInterfaceHandleTypeAlias(
 ) error {
 	embed, ok := getEmbeddedInterfaceForTypeExpr(typeSpec.Type, foundPkg.pkg)
 	if !ok {
-		return fmt.Errorf("name '%s' is not an interface", interfaceName)
+		return newPositionError(StageFind, foundPkg.pkg.Fset.Position(typeSpec.Pos()),
+			"name '%s' is not an interface", interfaceName)
 	}
 
 	embeddedPkg, err := f.loaded.loadPackageForInterfaces(embed.pkgPath, embed.name)
@@ -40,7 +50,11 @@ func (f *interfaceInfoFinder) getInterfaceInfoRecursive(
 ) error {
 	typeSpec := findInterfaceTypeSpec(interfaceName, foundPkg.pkg.Syntax)
 	if typeSpec == nil {
-		return fmt.Errorf("name '%s' is not a type spec", interfaceName)
+		return &Error{
+			Stage:     StageFind,
+			Interface: interfaceName,
+			Err:       fmt.Errorf("name '%s' is not a type spec", interfaceName),
+		}
 	}
 
 	interfaceType := findInterfaceAST(typeSpec)
@@ -53,50 +67,333 @@ func (f *interfaceInfoFinder) getInterfaceInfoRecursive(
 	for _, field := range interfaceType.Methods.List {
 		funcType, ok := field.Type.(*ast.FuncType)
 		if !ok {
-			embed, ok := getEmbeddedInterfaceForTypeExpr(field.Type, foundPkg.pkg)
-			if !ok {
-				continue
-			}
-
-			embeddedPkg, err := f.loaded.loadPackageForInterfaces(embed.pkgPath, embed.name)
-			if err != nil {
-				return err
-			}
-
-			err = f.getInterfaceInfoRecursive(embed.name, embeddedPkg)
+			err := f.getEmbeddedInterfaceInfo(field, foundPkg)
 			if err != nil {
 				return err
 			}
-
 			continue
 		}
 
 		ast.Walk(visitor, field)
 
-		params := fieldListToTupleList(funcType.Params, foundPkg.pkg.Fset, foundPkg.fileMap, foundPkg.pkg.TypesInfo)
-		results := fieldListToTupleList(funcType.Results, foundPkg.pkg.Fset, foundPkg.fileMap, foundPkg.pkg.TypesInfo)
-
-		f.methods = append(f.methods, methodType{
-			name:    field.Names[0].Name,
-			params:  params,
-			results: results,
-		})
+		method, err := f.getFieldMethod(field, funcType, foundPkg)
+		if err != nil {
+			return err
+		}
+		f.methods = append(f.methods, method)
 	}
 
 	return nil
 }
 
+// getEmbeddedInterfaceInfo adds the methods of an embedded interface, other embedded types are ignored
+func (f *interfaceInfoFinder) getEmbeddedInterfaceInfo(field *ast.Field, foundPkg loadedPackage) error {
+	embed, ok := getEmbeddedInterfaceForTypeExpr(field.Type, foundPkg.pkg)
+	if !ok {
+		return nil
+	}
+
+	embeddedPkg, err := f.loaded.loadPackageForInterfaces(embed.pkgPath, embed.name)
+	if err != nil {
+		return err
+	}
+	return f.getInterfaceInfoRecursive(embed.name, embeddedPkg)
+}
+
+// getFieldMethod reads the method of a field of an interface type, including its directives
+func (f *interfaceInfoFinder) getFieldMethod(
+	field *ast.Field, funcType *ast.FuncType, foundPkg loadedPackage,
+) (methodType, error) {
+	params := fieldListToTupleList(funcType.Params, foundPkg.pkg.Fset, foundPkg.fileMap, foundPkg.pkg.TypesInfo)
+	results := fieldListToTupleList(funcType.Results, foundPkg.pkg.Fset, foundPkg.fileMap, foundPkg.pkg.TypesInfo)
+
+	attributes, resultAttributes, err := getMethodAttributes(field, params, results, foundPkg.pkg)
+	if err != nil {
+		return methodType{}, err
+	}
+
+	spanKind, err := findSpanKind(foundPkg.pkg.Fset, field.Doc, field.Comment)
+	if err != nil {
+		return methodType{}, err
+	}
+
+	skipped, err := findSkip(foundPkg.pkg.Fset, field.Doc, field.Comment)
+	if err != nil {
+		return methodType{}, err
+	}
+
+	var location codeLocation
+	if f.withLocation {
+		location = getCodeLocation(field, foundPkg.pkg)
+	}
+
+	return methodType{
+		name:    field.Names[0].Name,
+		params:  params,
+		results: results,
+
+		attributes:       attributes,
+		resultAttributes: resultAttributes,
+		location:         location,
+		spanKind:         spanKind,
+		skipped:          skipped,
+		directives:       parseDirectives(field.Doc, field.Comment),
+	}, nil
+}
+
+// addFuncMethod adds the signature of a named func type as a method with the same name
+func (f *interfaceInfoFinder) addFuncMethod(typeSpec *ast.TypeSpec, funcType *ast.FuncType, foundPkg loadedPackage) {
+	pkg := foundPkg.pkg
+	ast.Walk(newImportVisitor(pkg.TypesInfo, f.visitorData), funcType)
+
+	var location codeLocation
+	if f.withLocation {
+		position := pkg.Fset.Position(typeSpec.Pos())
+		location = codeLocation{
+			filePath: pkg.PkgPath + "/" + filepath.Base(position.Filename),
+			line:     position.Line,
+		}
+	}
+
+	f.methods = append(f.methods, methodType{
+		name:    typeSpec.Name.Name,
+		params:  fieldListToTupleList(funcType.Params, pkg.Fset, foundPkg.fileMap, pkg.TypesInfo),
+		results: fieldListToTupleList(funcType.Results, pkg.Fset, foundPkg.fileMap, pkg.TypesInfo),
+
+		location: location,
+	})
+}
+
+func getCodeLocation(field *ast.Field, pkg *packages.Package) codeLocation {
+	position := pkg.Fset.Position(field.Pos())
+	return codeLocation{
+		filePath: pkg.PkgPath + "/" + filepath.Base(position.Filename),
+		line:     position.Line,
+	}
+}
+
+// getMethodAttributes returns the attributes of the params and the results of a method
+func getMethodAttributes(
+	field *ast.Field, params []tupleType, results []tupleType, pkg *packages.Package,
+) ([]spanAttribute, []spanAttribute, error) {
+	directives := parseDirectives(field.Doc, field.Comment)
+	if len(directives) == 0 {
+		return nil, nil, nil
+	}
+
+	signature := pkg.TypesInfo.Defs[field.Names[0]].Type().(*types.Signature)
+
+	var paramAttributes []spanAttribute
+	var resultAttributes []spanAttribute
+	for _, d := range directives {
+		switch d.name {
+		case directiveAttr:
+			attributes, err := parseAttributeDirective(d, pkg.Fset, params, signature)
+			if err != nil {
+				return nil, nil, err
+			}
+			paramAttributes = append(paramAttributes, attributes...)
+		case directiveResult:
+			attributes, err := parseResultDirective(d, pkg.Fset, results, signature)
+			if err != nil {
+				return nil, nil, err
+			}
+			resultAttributes = append(resultAttributes, attributes...)
+		}
+	}
+	return paramAttributes, resultAttributes, nil
+}
+
 func (f *interfaceInfoFinder) getInterfaceInfo(
 	interfaceName string,
 	foundPkg loadedPackage,
 ) (interfaceInfo, error) {
-	err := f.getInterfaceInfoRecursive(interfaceName, foundPkg)
+	if named, ok := findStructType(interfaceName, foundPkg.pkg); ok {
+		return f.getStructInfo(named, foundPkg)
+	}
+
+	typeSpec := findInterfaceTypeSpec(interfaceName, foundPkg.pkg.Syntax)
+	var funcType *ast.FuncType
+	if typeSpec != nil {
+		funcType, _ = typeSpec.Type.(*ast.FuncType)
+	}
+	if funcType != nil {
+		f.addFuncMethod(typeSpec, funcType, foundPkg)
+	} else {
+		err := f.getInterfaceInfoRecursive(interfaceName, foundPkg)
+		if err != nil {
+			return interfaceInfo{}, err
+		}
+	}
+
+	spanKind, err := findSpanKind(foundPkg.pkg.Fset, findTypeSpecDocs(interfaceName, foundPkg.pkg.Syntax)...)
 	if err != nil {
 		return interfaceInfo{}, err
 	}
 
-	return interfaceInfo{
-		name:    interfaceName,
-		methods: f.methods,
-	}, nil
+	info := interfaceInfo{
+		name:     interfaceName,
+		methods:  f.methods,
+		spanKind: spanKind,
+		funcType: funcType != nil,
+	}
+
+	if typeSpec.TypeParams == nil {
+		if f.typeArgs != nil {
+			return interfaceInfo{}, fmt.Errorf("interface '%s' is not generic", interfaceName)
+		}
+		return info, nil
+	}
+
+	pkg := foundPkg.pkg
+	info.typeParams = fieldListToTupleList(typeSpec.TypeParams, pkg.Fset, foundPkg.fileMap, pkg.TypesInfo)
+
+	if f.typeArgs == nil {
+		ast.Walk(newImportVisitor(pkg.TypesInfo, f.visitorData), typeSpec.TypeParams)
+		return info, nil
+	}
+	return f.instantiateInterface(info, typeSpec, foundPkg)
+}
+
+func (f *interfaceInfoFinder) instantiateInterface(
+	info interfaceInfo, typeSpec *ast.TypeSpec, foundPkg loadedPackage,
+) (interfaceInfo, error) {
+	if len(f.typeArgs) != len(info.typeParams) {
+		return interfaceInfo{}, fmt.Errorf("interface '%s' expects %d type arguments, got %d",
+			info.name, len(info.typeParams), len(f.typeArgs))
+	}
+
+	argTypes := make([]types.Type, 0, len(f.typeArgs))
+	argMap := map[string]tupleType{}
+	for i, arg := range f.typeArgs {
+		tuple, argType, err := f.parseTypeArg(arg, typeSpec.Pos(), foundPkg.pkg)
+		if err != nil {
+			return interfaceInfo{}, err
+		}
+		argTypes = append(argTypes, argType)
+		argMap[info.typeParams[i].name] = tuple
+		info.typeArgs = append(info.typeArgs, tuple)
+	}
+
+	generic := foundPkg.pkg.Types.Scope().Lookup(info.name).Type()
+	_, err := types.Instantiate(nil, generic, argTypes, true)
+	if err != nil {
+		return interfaceInfo{}, fmt.Errorf("can not instantiate interface '%s': %w", info.name, err)
+	}
+
+	methods := make([]methodType, 0, len(info.methods))
+	for _, method := range info.methods {
+		method.params = substituteTupleList(method.params, argMap)
+		method.results = substituteTupleList(method.results, argMap)
+		methods = append(methods, method)
+	}
+	info.methods = methods
+	info.typeParams = nil
+	return info, nil
+}
+
+func (f *interfaceInfoFinder) parseTypeArg(
+	arg string, pos token.Pos, pkg *packages.Package,
+) (tupleType, types.Type, error) {
+	arg = strings.TrimSpace(arg)
+	expr, err := parser.ParseExprFrom(pkg.Fset, "type argument", arg, 0)
+	if err != nil {
+		return tupleType{}, nil, fmt.Errorf("invalid type argument '%s': %w", arg, err)
+	}
+
+	info := &types.Info{
+		Types: map[ast.Expr]types.TypeAndValue{},
+		Uses:  map[*ast.Ident]types.Object{},
+	}
+	err = types.CheckExpr(pkg.Fset, pkg.Types, pos, expr, info)
+	if err != nil {
+		return tupleType{}, nil, fmt.Errorf("invalid type argument '%s': %w", arg, err)
+	}
+	typeAndValue := info.Types[expr]
+	if !typeAndValue.IsType() {
+		return tupleType{}, nil, fmt.Errorf("type argument '%s' is not a type", arg)
+	}
+
+	visitor := &tupleVisitor{begin: expr.Pos(), info: info}
+	ast.Walk(visitor, expr)
+	ast.Walk(newImportVisitor(info, f.visitorData), expr)
+
+	return tupleType{
+		typeStr:    arg,
+		recognized: recognizedTypeOf(typeAndValue.Type),
+		pkgList:    visitor.pkgList,
+	}, typeAndValue.Type, nil
+}
+
+func shiftTupleTypePkg(pkg tupleTypePkg, offset int) tupleTypePkg {
+	pkg.begin += offset
+	pkg.end += offset
+	return pkg
+}
+
+// substituteSignature replaces the type parameters in the params and results of a func literal type
+func substituteSignature(signature *signatureType, args map[string]tupleType) *signatureType {
+	if signature == nil {
+		return nil
+	}
+	return &signatureType{
+		params:  substituteTupleList(signature.params, args),
+		results: substituteTupleList(signature.results, args),
+	}
+}
+
+// substituteTypeParams replaces type parameters in the type string by the type arguments
+func substituteTypeParams(tuple tupleType, args map[string]tupleType) tupleType {
+	if len(tuple.typeParamRefs) == 0 {
+		return tuple
+	}
+
+	result := tuple
+	result.pkgList = nil
+	result.typeParamRefs = nil
+
+	var buf strings.Builder
+	shift := 0
+	from := 0
+	pkgIndex := 0
+	for _, ref := range tuple.typeParamRefs {
+		for ; pkgIndex < len(tuple.pkgList) && tuple.pkgList[pkgIndex].begin < ref.begin; pkgIndex++ {
+			result.pkgList = append(result.pkgList, shiftTupleTypePkg(tuple.pkgList[pkgIndex], shift))
+		}
+
+		arg := args[ref.name]
+		_, _ = buf.WriteString(tuple.typeStr[from:ref.begin])
+		argBegin := buf.Len()
+		_, _ = buf.WriteString(arg.typeStr)
+		for _, pkg := range arg.pkgList {
+			result.pkgList = append(result.pkgList, shiftTupleTypePkg(pkg, argBegin))
+		}
+
+		shift += len(arg.typeStr) - (ref.end - ref.begin)
+		from = ref.end
+	}
+	for ; pkgIndex < len(tuple.pkgList); pkgIndex++ {
+		result.pkgList = append(result.pkgList, shiftTupleTypePkg(tuple.pkgList[pkgIndex], shift))
+	}
+	_, _ = buf.WriteString(tuple.typeStr[from:])
+
+	result.typeStr = buf.String()
+	result.signature = substituteSignature(tuple.signature, args)
+
+	ref := tuple.typeParamRefs[0]
+	if len(tuple.typeParamRefs) == 1 && ref.begin == 0 && ref.end == len(tuple.typeStr) {
+		result.recognized = args[ref.name].recognized
+	}
+	return result
+}
+
+func substituteTupleList(tuples []tupleType, args map[string]tupleType) []tupleType {
+	if tuples == nil {
+		return nil
+	}
+	result := make([]tupleType, 0, len(tuples))
+	for _, tuple := range tuples {
+		result = append(result, substituteTypeParams(tuple, args))
+	}
+	return result
 }
@@ -27,7 +27,7 @@ func (loaded loadedPackages) loadPackageForInterfaces(
 		Mode: loadPackageMode,
 	}, pattern)
 	if err != nil {
-		return loadedPackage{}, err
+		return loadedPackage{}, WrapError(err, StageLoad, "")
 	}
 
 	foundPkg, err := checkAndFindPackageForInterfaces(pkgList, interfaceNames...)
@@ -0,0 +1,172 @@
+package generate
+
+import (
+	"fmt"
+	"go/types"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// interfaceSelection selects the interfaces of a package by glob patterns of names, e.g. Repo*,
+// and regular expressions matched against the names of all interfaces of the package
+type interfaceSelection struct {
+	match   *regexp.Regexp // nil if the interfaces are not selected by a regular expression
+	exclude *regexp.Regexp // excludes interfaces selected by glob patterns or by match
+}
+
+func newInterfaceSelection(conf generateConfig) (interfaceSelection, error) {
+	var selection interfaceSelection
+	if conf.match != "" {
+		match, err := regexp.Compile(conf.match)
+		if err != nil {
+			return interfaceSelection{}, WrapError(fmt.Errorf("invalid match expression: %w", err), StageArgs, "")
+		}
+		selection.match = match
+	}
+	if conf.exclude != "" {
+		exclude, err := regexp.Compile(conf.exclude)
+		if err != nil {
+			return interfaceSelection{}, WrapError(fmt.Errorf("invalid exclude expression: %w", err), StageArgs, "")
+		}
+		selection.exclude = exclude
+	}
+	return selection, nil
+}
+
+func isGlobPattern(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+// explicitInterfaceNames returns the names that are not glob patterns
+func explicitInterfaceNames(interfaceNames []string) []string {
+	var result []string
+	for _, name := range interfaceNames {
+		if !isGlobPattern(name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// scopeInterfaceNames returns the names of the interface types declared in the scope, sorted by name,
+// excluding constraint interfaces, which can not be implemented, and aliases of the interfaces
+func scopeInterfaceNames(scope *types.Scope) []string {
+	var result []string
+	for _, name := range scope.Names() {
+		typeName, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || typeName.IsAlias() {
+			continue
+		}
+		iface, ok := typeName.Type().Underlying().(*types.Interface)
+		if ok && iface.IsMethodSet() {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+func hasGlobPatterns(interfaceNames []string) bool {
+	for _, name := range interfaceNames {
+		if isGlobPattern(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectByPattern adds the candidates matching the glob pattern to selected
+func (s interfaceSelection) selectByPattern(candidates []string, pattern string, selected map[string]bool) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return WrapError(fmt.Errorf("invalid pattern '%s': %w", pattern, err), StageArgs, "")
+	}
+	found := false
+	for _, candidate := range candidates {
+		if ok, _ := path.Match(pattern, candidate); ok && !s.excluded(candidate) {
+			selected[candidate] = true
+			found = true
+		}
+	}
+	if !found {
+		return &Error{
+			Stage: StageFind,
+			Err:   fmt.Errorf("no interface matches '%s'", pattern),
+		}
+	}
+	return nil
+}
+
+// selectByMatch adds the candidates matching the regular expression to selected
+func (s interfaceSelection) selectByMatch(candidates []string, selected map[string]bool) error {
+	found := false
+	for _, candidate := range candidates {
+		if s.match.MatchString(candidate) && !s.excluded(candidate) {
+			selected[candidate] = true
+			found = true
+		}
+	}
+	if !found {
+		return &Error{
+			Stage: StageFind,
+			Err:   fmt.Errorf("no interface matches the expression '%s'", s.match),
+		}
+	}
+	return nil
+}
+
+// selectInterfaces expands the glob patterns of the interface names, adding the interfaces matching
+// the regular expression if withMatch is true. The names are kept in order if nothing is selected by patterns,
+// otherwise the result is sorted by name. The second result is the list of the selected names
+//
+//revive:disable-next-line:flag-parameter
+func (s interfaceSelection) selectInterfaces(
+	scope *types.Scope, interfaceNames []string, withMatch bool,
+) ([]string, []string, error) {
+	withMatch = withMatch && s.match != nil
+	if !withMatch && !hasGlobPatterns(interfaceNames) {
+		return interfaceNames, nil, nil
+	}
+
+	candidates := scopeInterfaceNames(scope)
+	explicit := map[string]bool{}
+	selected := map[string]bool{}
+	for _, name := range interfaceNames {
+		if !isGlobPattern(name) {
+			explicit[name] = true
+			continue
+		}
+		if err := s.selectByPattern(candidates, name, selected); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if withMatch {
+		if err := s.selectByMatch(candidates, selected); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	result, selectedNames := mergeSelectedNames(explicit, selected)
+	return result, selectedNames, nil
+}
+
+// mergeSelectedNames returns the sorted union of the explicit and the selected names, and the sorted selected names
+func mergeSelectedNames(explicit map[string]bool, selected map[string]bool) (result []string, selectedNames []string) {
+	for name := range explicit {
+		result = append(result, name)
+	}
+	for name := range selected {
+		selectedNames = append(selectedNames, name)
+		if !explicit[name] {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	sort.Strings(selectedNames)
+	return result, selectedNames
+}
+
+func (s interfaceSelection) excluded(name string) bool {
+	return s.exclude != nil && s.exclude.MatchString(name)
+}
@@ -0,0 +1,88 @@
+package generate
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func interfaceNamesOf(info packageTypeInfo) []string {
+	var result []string
+	for _, interfaceDetail := range info.interfaces {
+		result = append(result, interfaceDetail.name)
+	}
+	return result
+}
+
+func TestLoadPackageTypeInfo_With_Glob_Patterns(t *testing.T) {
+	var report bytes.Buffer
+	info, err := loadPackageTypeDataWithConfig("./hello", []string{"Processor", "S*"},
+		computeGenerateConfig(WithMatchReport(&report)))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"Processor", "Simple", "StreamHandler", "Subscriber"}, interfaceNamesOf(info))
+	assert.Equal(t, "github.com/QuangTung97/otelwrap/internal/generate/hello: "+
+		"matched Simple, StreamHandler, Subscriber\n", report.String())
+}
+
+func TestLoadPackageTypeInfo_Without_Patterns_Keep_Order(t *testing.T) {
+	var report bytes.Buffer
+	info, err := loadPackageTypeDataWithConfig("./hello", []string{"Simple", "Processor"},
+		computeGenerateConfig(WithMatchReport(&report), WithExclude("Simple")))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"Simple", "Processor"}, interfaceNamesOf(info))
+	assert.Equal(t, "", report.String())
+}
+
+func TestLoadPackageTypeInfo_With_Match_And_Exclude(t *testing.T) {
+	info, err := loadPackageTypeDataWithConfig("./hello", nil,
+		computeGenerateConfig(WithMatch("Handler$"), WithExclude("^(Invalid|Generic)")))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{
+		"AttributeHandler",
+		"Handler",
+		"KindHandler",
+		"MixedContextHandler",
+		"ResultHandler",
+		"StreamHandler",
+	}, interfaceNamesOf(info))
+}
+
+func TestLoadPackageTypeInfo_Match_Without_Constraint_Interfaces(t *testing.T) {
+	info, err := loadPackageTypeDataWithConfig("./hello", nil, computeGenerateConfig(WithMatch("^N")))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"NumberRepo"}, interfaceNamesOf(info))
+
+	_, err = loadPackageTypeDataWithConfig("./hello", []string{"Number*"}, computeGenerateConfig(WithExclude("Repo")))
+	assert.Equal(t, &Error{
+		Stage: StageFind,
+		Err:   errors.New("no interface matches 'Number*'"),
+	}, err)
+}
+
+func TestLoadPackageTypeInfo_Glob_Pattern_Not_Matched(t *testing.T) {
+	_, err := loadPackageTypeDataWithConfig("./hello", []string{"Invalid*"},
+		computeGenerateConfig(WithExclude("Invalid")))
+	assert.Equal(t, &Error{
+		Stage: StageFind,
+		Err:   errors.New("no interface matches 'Invalid*'"),
+	}, err)
+}
+
+func TestLoadPackageTypeInfo_Invalid_Match_Expression(t *testing.T) {
+	_, err := loadPackageTypeDataWithConfig("./hello", nil, computeGenerateConfig(WithMatch("(")))
+
+	var genErr *Error
+	assert.Equal(t, true, errors.As(err, &genErr))
+	assert.Equal(t, StageArgs, genErr.Stage)
+	assert.Equal(t, "invalid match expression: error parsing regexp: missing closing ): `(`", genErr.Err.Error())
+}
+
+func TestLoadPackageTypeInfo_Groups_Match_Only_First_Group(t *testing.T) {
+	info, err := loadPackageTypeDataForGroups([]InterfaceGroup{
+		{Pattern: "./hello/embed"},
+		{Pattern: "./hello", InterfaceNames: []string{"Simple"}},
+	}, computeGenerateConfig(WithMatch("er$")))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"Parser", "Scanner", "Simple"}, interfaceNamesOf(info))
+}
@@ -0,0 +1,171 @@
+package generate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"golang.org/x/tools/go/packages"
+	"io"
+	"path/filepath"
+)
+
+const directiveWrap = "wrap"
+
+// DefaultScanOutFile is the output file name when the wrap directive does not specify one
+const DefaultScanOutFile = "otelwrap_wrappers.go"
+
+// ScanTarget is a generated file containing wrappers of interfaces with the wrap directive
+type ScanTarget struct {
+	OutFile        string
+	InterfaceNames []string
+
+	pkg        loadedPackage
+	namePrefix string
+}
+
+type wrapDirective struct {
+	interfaceName string
+	outFile       string
+	namePrefix    string
+}
+
+func parseWrapDirective(d directive, fset *token.FileSet, interfaceName string) (wrapDirective, error) {
+	result := wrapDirective{
+		interfaceName: interfaceName,
+		outFile:       DefaultScanOutFile,
+	}
+	for _, arg := range d.args {
+		key, value, ok := splitKeyValue(arg)
+		if !ok {
+			return wrapDirective{}, newDirectiveError(fset, d, "invalid argument '%s', expected key=value", arg)
+		}
+		switch key {
+		case "out":
+			result.outFile = value
+		case "prefix":
+			result.namePrefix = value
+		default:
+			return wrapDirective{}, newDirectiveError(fset, d, "unknown setting '%s'", key)
+		}
+	}
+	return result, nil
+}
+
+func findDeclWrapDirectives(fset *token.FileSet, genDecl *ast.GenDecl) ([]wrapDirective, error) {
+	var result []wrapDirective
+	for _, spec := range genDecl.Specs {
+		typeSpec := spec.(*ast.TypeSpec)
+
+		for _, d := range parseDirectives(typeSpecDocs(genDecl, typeSpec)...) {
+			if d.name != directiveWrap {
+				continue
+			}
+			wrap, err := parseWrapDirective(d, fset, typeSpec.Name.Name)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, wrap)
+		}
+	}
+	return result, nil
+}
+
+func findWrapDirectives(pkg *packages.Package) ([]wrapDirective, error) {
+	var result []wrapDirective
+	for _, syntax := range pkg.Syntax {
+		for _, decl := range syntax.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			directives, err := findDeclWrapDirectives(pkg.Fset, genDecl)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, directives...)
+		}
+	}
+	return result, nil
+}
+
+func packageDir(pkg *packages.Package) string {
+	if len(pkg.GoFiles) == 0 {
+		return ""
+	}
+	return filepath.Dir(pkg.GoFiles[0])
+}
+
+// Scanner loads packages once and finds interfaces with the wrap directive
+type Scanner struct {
+	loaded  loadedPackages
+	Targets []ScanTarget
+}
+
+// Scan loads the packages matching the patterns and groups interfaces with the wrap directive by output file
+func Scan(patterns ...string) (*Scanner, error) {
+	pkgList, err := packages.Load(&packages.Config{
+		Mode: loadPackageMode | packages.NeedFiles,
+	}, patterns...)
+	if err != nil {
+		return nil, WrapError(err, StageLoad, "")
+	}
+
+	scanner := &Scanner{
+		loaded: loadedPackages{},
+	}
+	for _, pkg := range pkgList {
+		scanner.loaded[pkg.PkgPath] = loadedPackage{
+			pkg:     pkg,
+			fileMap: readFiles(pkg.CompiledGoFiles),
+		}
+	}
+
+	for _, pkg := range pkgList {
+		directives, err := findWrapDirectives(pkg)
+		if err != nil {
+			return nil, err
+		}
+
+		targetIndex := map[string]int{}
+		for _, wrap := range directives {
+			outFile := filepath.Join(packageDir(pkg), wrap.outFile)
+
+			index, existed := targetIndex[outFile]
+			if !existed {
+				index = len(scanner.Targets)
+				targetIndex[outFile] = index
+				scanner.Targets = append(scanner.Targets, ScanTarget{
+					OutFile:    outFile,
+					pkg:        scanner.loaded[pkg.PkgPath],
+					namePrefix: wrap.namePrefix,
+				})
+			}
+
+			target := &scanner.Targets[index]
+			if target.namePrefix != wrap.namePrefix {
+				return nil, &Error{
+					Stage:     StageFind,
+					Interface: wrap.interfaceName,
+					Err:       fmt.Errorf("different prefix for output file '%s'", outFile),
+				}
+			}
+			target.InterfaceNames = append(target.InterfaceNames, wrap.interfaceName)
+		}
+	}
+	return scanner, nil
+}
+
+// Generate writes the wrappers for a scanned target
+func (s *Scanner) Generate(w io.Writer, target ScanTarget, options ...Option) error {
+	conf := computeGenerateConfig(options...)
+	info, err := s.loaded.packageTypeData(target.pkg, target.InterfaceNames, conf)
+	if err != nil {
+		return err
+	}
+
+	options = append(options, WithNamePrefix(target.namePrefix))
+	if filepath.Dir(target.OutFile) != packageDir(target.pkg.pkg) {
+		options = append(options, WithInAnotherPackage(filepath.Base(filepath.Dir(target.OutFile))))
+	}
+	return WrapError(generateCode(w, info, options...), StageGenerate, "")
+}
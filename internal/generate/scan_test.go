@@ -0,0 +1,92 @@
+package generate
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestScan(t *testing.T) {
+	scanner, err := Scan("./hello/...")
+	assert.Equal(t, nil, err)
+
+	dir, err := filepath.Abs("./hello/scan")
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, 2, len(scanner.Targets))
+
+	assert.Equal(t, filepath.Join(dir, "otelwrap_wrappers.go"), scanner.Targets[0].OutFile)
+	assert.Equal(t, []string{"Repo", "Cache"}, scanner.Targets[0].InterfaceNames)
+
+	assert.Equal(t, filepath.Join(dir, "client_wrappers.go"), scanner.Targets[1].OutFile)
+	assert.Equal(t, []string{"Client"}, scanner.Targets[1].InterfaceNames)
+
+	var buf bytes.Buffer
+	err = scanner.Generate(&buf, scanner.Targets[1])
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package scan
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// TracedClientWrapper wraps OpenTelemetry's span
+type TracedClientWrapper struct {
+	Client
+	tracer trace.Tracer
+	prefix string
+}
+
+var _ Client = (*TracedClientWrapper)(nil)
+
+// NewTracedClientWrapper creates a wrapper
+func NewTracedClientWrapper(wrapped Client, tracer trace.Tracer, prefix string) *TracedClientWrapper {
+	return &TracedClientWrapper{
+		Client: wrapped,
+		tracer: tracer,
+		prefix: prefix,
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *TracedClientWrapper) Unwrap() Client {
+	return w.Client
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *TracedClientWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Client
+}
+
+// Call ...
+func (w *TracedClientWrapper) Call(ctx context.Context) (err error) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "Call")
+	defer span.End()
+
+	err = w.Client.Call(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+`, buf.String())
+}
+
+func TestScan_Embedded_Interface_From_Scanned_Package(t *testing.T) {
+	scanner, err := Scan("./hello/...")
+	assert.Equal(t, nil, err)
+
+	var buf bytes.Buffer
+	err = scanner.Generate(&buf, scanner.Targets[0])
+	assert.Equal(t, nil, err)
+	assert.Contains(t, buf.String(), "func (w *RepoWrapper) Compute(ctx context.Context, x string) (err error) {")
+	assert.Contains(t, buf.String(), "func (w *CacheWrapper) Set(ctx context.Context, key string) {")
+}
@@ -0,0 +1,165 @@
+package generate
+
+import (
+	"bytes"
+	"go/token"
+	"go/types"
+	"golang.org/x/tools/go/packages"
+	"path/filepath"
+)
+
+// structInterfaceSuffix is appended to the name of a struct type for naming the interface of its methods
+const structInterfaceSuffix = "API"
+
+// findStructType returns the named type if typeName is a struct type of the package
+func findStructType(typeName string, pkg *packages.Package) (*types.Named, bool) {
+	object, ok := pkg.Types.Scope().Lookup(typeName).(*types.TypeName)
+	if !ok {
+		return nil, false
+	}
+	named, ok := object.Type().(*types.Named)
+	if !ok {
+		return nil, false
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil, false
+	}
+	return named, true
+}
+
+// tupleTypeWriter writes types, recording the positions of package names for replacing them later
+type tupleTypeWriter struct {
+	rootPkg     *types.Package
+	visitorData *importVisitorData
+
+	buf     bytes.Buffer
+	pkgList []tupleTypePkg
+}
+
+func (w *tupleTypeWriter) qualifier(pkg *types.Package) string {
+	begin := w.buf.Len()
+	if pkg == w.rootPkg {
+		w.pkgList = append(w.pkgList, tupleTypePkg{
+			path:  pkg.Path(),
+			begin: begin,
+			end:   begin,
+		})
+		return ""
+	}
+
+	w.visitorData.append([]importInfo{
+		{
+			name: pkg.Name(),
+			path: pkg.Path(),
+		},
+	})
+	w.pkgList = append(w.pkgList, tupleTypePkg{
+		path:  pkg.Path(),
+		begin: begin,
+		end:   begin + len(pkg.Name()),
+	})
+	return pkg.Name()
+}
+
+//revive:disable-next-line:flag-parameter
+func (w *tupleTypeWriter) varToTuple(v *types.Var, isVariadic bool) tupleType {
+	w.buf.Reset()
+	w.pkgList = nil
+
+	varType := v.Type()
+	if isVariadic {
+		_, _ = w.buf.WriteString("...")
+		varType = varType.(*types.Slice).Elem()
+	}
+	types.WriteType(&w.buf, varType, w.qualifier)
+
+	result := tupleType{
+		name:       v.Name(),
+		typeStr:    w.buf.String(),
+		recognized: recognizedTypeOf(v.Type()),
+		isVariadic: isVariadic,
+		pkgList:    w.pkgList,
+	}
+	if signature, ok := v.Type().(*types.Signature); ok {
+		result.signature = &signatureType{
+			params:  w.tupleToList(signature.Params(), signature.Variadic()),
+			results: w.tupleToList(signature.Results(), false),
+		}
+	}
+	return result
+}
+
+func (w *tupleTypeWriter) tupleToList(tuple *types.Tuple, variadic bool) []tupleType {
+	if tuple.Len() == 0 {
+		return nil
+	}
+	result := make([]tupleType, 0, tuple.Len())
+	for i := 0; i < tuple.Len(); i++ {
+		isVariadic := variadic && i == tuple.Len()-1
+		result = append(result, w.varToTuple(tuple.At(i), isVariadic))
+	}
+	return result
+}
+
+func getFuncCodeLocation(fn *types.Func, fset *token.FileSet) codeLocation {
+	position := fset.Position(fn.Pos())
+	return codeLocation{
+		filePath: fn.Pkg().Path() + "/" + filepath.Base(position.Filename),
+		line:     position.Line,
+	}
+}
+
+// getStructInfo synthesizes an interface from the exported methods in the method set of the pointer to a struct type
+func (f *interfaceInfoFinder) getStructInfo(named *types.Named, foundPkg loadedPackage) (interfaceInfo, error) {
+	structName := named.Obj().Name()
+	pos := foundPkg.pkg.Fset.Position(named.Obj().Pos())
+	if named.TypeParams().Len() > 0 {
+		return interfaceInfo{}, newPositionError(StageFind, pos,
+			"generic struct type '%s' is not supported", structName)
+	}
+	if f.typeArgs != nil {
+		return interfaceInfo{}, newPositionError(StageFind, pos, "struct type '%s' is not generic", structName)
+	}
+
+	writer := &tupleTypeWriter{
+		rootPkg:     foundPkg.pkg.Types,
+		visitorData: f.visitorData,
+	}
+
+	methodSet := types.NewMethodSet(types.NewPointer(named))
+	for i := 0; i < methodSet.Len(); i++ {
+		fn := methodSet.At(i).Obj().(*types.Func)
+		if !fn.Exported() {
+			continue
+		}
+		signature := fn.Type().(*types.Signature)
+
+		var location codeLocation
+		if f.withLocation && fn.Pos().IsValid() {
+			location = getFuncCodeLocation(fn, foundPkg.pkg.Fset)
+		}
+
+		f.methods = append(f.methods, methodType{
+			name:    fn.Name(),
+			params:  writer.tupleToList(signature.Params(), signature.Variadic()),
+			results: writer.tupleToList(signature.Results(), false),
+
+			location: location,
+		})
+	}
+	if len(f.methods) == 0 {
+		return interfaceInfo{}, newPositionError(StageFind, pos, "struct type '%s' has no exported methods", structName)
+	}
+
+	spanKind, err := findSpanKind(foundPkg.pkg.Fset, findTypeSpecDocs(structName, foundPkg.pkg.Syntax)...)
+	if err != nil {
+		return interfaceInfo{}, err
+	}
+
+	return interfaceInfo{
+		name:       structName + structInterfaceSuffix,
+		structName: structName,
+		methods:    f.methods,
+		spanKind:   spanKind,
+	}, nil
+}
@@ -1,9 +1,10 @@
 package generate
 
 import (
+	"bytes"
 	"fmt"
+	"go/format"
 	"io"
-	"path"
 	"strings"
 	"text/template"
 )
@@ -16,59 +17,416 @@ import (
 	{{ . }}{{ end }}
 )
 {{ range $interface := .Interfaces }}
+{{- if .Func }}{{ template "func" $interface }}{{ else }}
+{{- with .Struct }}
+// {{ $interface.UsedName }} is the interface of the exported methods of *{{ .Name }}
+type {{ $interface.UsedName }} interface {
+	{{- range .Methods }}
+	{{ . }}
+	{{- end }}
+}
+
+var _ {{ $interface.UsedName }} = (*{{ .Name }})(nil)
+{{ end }}
 // {{ .StructName }} wraps OpenTelemetry's span
-type {{ .StructName }} struct {
-	{{ .Name }}
+{{- with .SkippedMethods }}
+//
+// Skipped methods, not traced: {{ join . ", " }}
+{{- end }}
+type {{ .StructName }}{{ .TypeParams }} struct {
+	{{ if .NoEmbed }}{{ .Field }} {{ end }}{{ .Name }}{{ .InterfaceTypeArgs }}
 	tracer {{ .ChosenOtelTracer }}
+	{{- with .Options }}
+	spanName {{ .SupportPkg }}.SpanNameFormatter
+	startOptions []{{ .SpanStartOption }}
+	{{- range .KindStartOptions }}
+	{{ .Field }} []{{ $interface.Options.SpanStartOption }}
+	{{- end }}
+	{{- else }}
 	prefix string
+	{{- end }}
+	{{- with .Classifier }}
+	classifier {{ .SupportPkg }}.ErrorClassifier
+	{{- end }}
+	{{- with .Code }}
+	codeAttributes [][]{{ .KeyValue }}
+	{{- end }}
+	{{- with .Fallback }}
+	fallbackCtx {{ .ContextPkg }}.Context
+	{{- end }}
 }
-
+{{ if not .TypeParams }}
+var _ {{ .Name }}{{ .InterfaceTypeArgs }} = (*{{ .StructName }})(nil)
+{{ end }}{{ with .Options }}
+// New{{ $interface.StructName }} creates a wrapper
+func New{{ $interface.StructName }}{{ $interface.TypeParams }}(
+	{{- "" }}wrapped {{ $interface.Name }}{{ $interface.InterfaceTypeArgs }}, opts ...{{ .SupportPkg }}.Option
+	{{- "" }}) *{{ $interface.StructName }}{{ $interface.TypeArgs }} {
+	conf := {{ .SupportPkg }}.NewConfig({{ printf "%q" .ScopeName }}, opts...)
+	{{- with $interface.Classifier }}
+	classifier := {{ if .Param }}conf.ErrorClassifier{{ else }}{{ .Default }}{{ end }}
+	{{- if .Param }}
+	if classifier == nil {
+		classifier = {{ .Default }}
+	}
+	{{- end }}
+	{{- end }}
+	{{- with $interface.Fallback }}
+	fallbackCtx := conf.FallbackContext
+	if fallbackCtx == nil {
+		fallbackCtx = {{ .ContextPkg }}.Background()
+	}
+	{{- end }}
+	return &{{ $interface.StructName }}{{ $interface.TypeArgs }}{
+		{{ $interface.Field }}: wrapped,
+		tracer: conf.Tracer,
+		spanName: conf.SpanNameFormatter,
+		startOptions: conf.SpanStartOptions,
+		{{- range .KindStartOptions }}
+		{{ .Field }}: append([]{{ $interface.Options.SpanStartOption }}{ {{- .SpanKind -}} }, conf.SpanStartOptions...),
+		{{- end }}
+		{{- if $interface.Classifier }}
+		classifier: classifier,
+		{{- end }}
+		{{- if $interface.Fallback }}
+		fallbackCtx: fallbackCtx,
+		{{- end }}
+		{{- with $interface.Code }}
+		codeAttributes: [][]{{ .KeyValue }}{
+			{{- range .Methods }}
+			{
+				{{- range . }}
+				{{ . }},
+				{{- end }}
+			},
+			{{- end }}
+		},
+		{{- end }}
+	}
+}
+{{ else }}
 // New{{ .StructName }} creates a wrapper
-func New{{ .StructName }}(wrapped {{ .Name}}, tracer {{ .ChosenOtelTracer }}, prefix string) *{{ .StructName }} {
-	return &{{ .StructName }}{
-		{{ .UsedName }}: wrapped,
+func New{{ .StructName }}{{ .TypeParams }}(wrapped {{ .Name }}{{ .InterfaceTypeArgs }},
+	{{- " " }}tracer {{ .ChosenOtelTracer }}, prefix string
+	{{- with .Classifier }}{{ if .Param }}, classifier {{ .SupportPkg }}.ErrorClassifier{{ end }}{{ end -}}
+	{{- with .Fallback }}, fallbackCtx {{ .ContextPkg }}.Context{{ end -}}
+) *{{ .StructName }}{{ .TypeArgs }} {
+	{{- with .Classifier }}{{ if .Param }}
+	if classifier == nil {
+		classifier = {{ .Default }}
+	}
+	{{- end }}{{ end }}
+	{{- with .Fallback }}
+	if fallbackCtx == nil {
+		fallbackCtx = {{ .ContextPkg }}.Background()
+	}
+	{{- end }}
+	return &{{ .StructName }}{{ .TypeArgs }}{
+		{{ .Field }}: wrapped,
 		tracer: tracer,
 		prefix: prefix,
+		{{- with .Classifier }}
+		classifier: {{ if .Param }}classifier{{ else }}{{ .Default }}{{ end }},
+		{{- end }}
+		{{- if $interface.Fallback }}
+		fallbackCtx: fallbackCtx,
+		{{- end }}
+		{{- with $interface.Code }}
+		codeAttributes: [][]{{ .KeyValue }}{
+			{{- range .Methods }}
+			{
+				{{- range . }}
+				{{ . }},
+				{{- end }}
+			},
+			{{- end }}
+		},
+		{{- end }}
 	}
 }
-{{ range .Methods }}
+{{ end -}}
+{{ if .Unwrap }}
+// Unwrap returns the wrapped implementation
+func (w *{{ .StructName }}{{ .TypeArgs }}) Unwrap() {{ .Name }}{{ .InterfaceTypeArgs }} {
+	return w.{{ .Field }}
+}
+{{ end }}
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *{{ .StructName }}{{ .TypeArgs }}) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.{{ .Field }}
+}
+{{ range $method := .Methods }}
 // {{ .Name }} ...
-func (w *{{ $interface.StructName }}) {{ .Name }}{{ .ParamsString }}{{ .ResultsString }}{
-	{{ .CtxName }}, {{ .SpanName }} := w.tracer.Start({{ .CtxName }}, w.prefix + "{{ .Name }}")
+func (w *{{ $interface.StructName }}{{ $interface.TypeArgs }}) {{ .Name }}{{ .ParamsString }}{{ .ResultsString }}{
+	{{- if .Forward }}
+	{{ if .WithReturn }}return {{ end }}w.{{ $interface.Field }}.{{ .Name }}({{ .ArgsString }})
+	{{- else }}
+	{{ .CtxName }}, {{ .SpanName }} := w.tracer.Start({{ .ParentCtx }},
+		{{- if $interface.Options }} w.spanName("{{ $interface.UsedName }}", "{{ .Name }}"), w.{{ .StartOptions }}...)
+		{{- else }} w.prefix + "{{ .Name }}"{{ with .SpanKind }}, {{ . }}{{ end }})
+		{{- end }}
+	{{- with .Stream }}
+	{{ .Var }} := false
+	{{- end }}
+	{{- with $interface.Panics }}
+	defer func() {
+		if r := recover(); r != nil {
+			{{ $method.SpanName }}.RecordError({{ .FmtPkg }}.Errorf("panic: %v", r), {{ .WithStackTrace }}(true))
+			{{ $method.SpanName }}.SetStatus({{ $method.ChosenOtelCodes }}, {{ .FmtPkg }}.Sprint(r))
+			{{ $method.SpanName }}.End()
+			panic(r)
+		}
+		{{- with $method.Stream }}
+		if !{{ .Var }} {
+			{{ $method.SpanName }}.End()
+		}
+		{{- else }}
+		{{ $method.SpanName }}.End()
+		{{- end }}
+	}()
+	{{- else }}{{ with .Stream }}
+	defer func() {
+		if !{{ .Var }} {
+			{{ $method.SpanName }}.End()
+		}
+	}()
+	{{- else }}
 	defer {{ .SpanName }}.End()
+	{{- end }}{{ end }}
+	{{- with .ContextUpdate }}
+	{{ . }}
+	{{- end }}
+	{{- if $interface.Code }}
+	{{ .SpanName }}.SetAttributes(w.codeAttributes[{{ .Index }}]...)
+	{{- end }}
+	{{- if .Attributes }}
+	{{ .SpanName }}.SetAttributes(
+		{{- range .Attributes }}
+		{{ . }},
+		{{- end }}
+	)
+	{{- end }}
+	{{- with .Link }}
+	{{ . }}
+	{{- end }}
+	{{- range .Callbacks }}
+	{{ template "callback" . }}
+	{{- end }}
 
 	{{ if .WithReturn -}}
-	{{ .ResultsRecvString }} = w.{{ $interface.UsedName }}.{{ .Name }}({{ .ArgsString }})
+	{{ .ResultsRecvString }} = w.{{ $interface.Field }}.{{ .Name }}({{ .ArgsString }})
 	{{ if .WithError -}}
 	if {{ .ErrString }} != nil {
+		{{- with $interface.Classifier }}
+		switch w.classifier({{ $method.ErrString }}) {
+		case {{ .SupportPkg }}.ErrorFailed:
+			{{ $method.SpanName }}.RecordError({{ $method.ErrString }})
+			{{ $method.SpanName }}.SetStatus({{ $method.ChosenOtelCodes }}, {{ $method.ErrString }}.Error())
+		case {{ .SupportPkg }}.ErrorRecorded:
+			{{ $method.SpanName }}.RecordError({{ $method.ErrString }})
+		}
+		{{- else }}
 		{{ .SpanName }}.RecordError({{ .ErrString }})
 		{{ .SpanName }}.SetStatus({{ .ChosenOtelCodes }}, {{ .ErrString }}.Error())
+		{{- end }}
+	}
+	{{- end }}
+	{{- if .ResultAttributes }}
+	{{ .SpanName }}.SetAttributes(
+		{{- range .ResultAttributes }}
+		{{ . }},
+		{{- end }}
+	)
+	{{- end }}
+	{{- with .Stream }}
+	if {{ .Result }} != nil {
+		{{ .Result }} = {{ .Wrap }}
+		{{ .Var }} = true
 	}
 	{{- end }}
+	{{- range .ResultCallbacks }}
+	{{ template "callback" . }}
+	{{- end }}
 	return {{ .ResultsRecvString }}
 	{{- else -}}
-	w.{{ $interface.UsedName }}.{{ .Name }}({{ .ArgsString }})
+	w.{{ $interface.Field }}.{{ .Name }}({{ .ArgsString }})
+	{{- end }}
 	{{- end }}
 }
 {{ end -}}
+{{ with .Metrics }}{{ template "metrics" $interface }}{{ end -}}
+{{ end -}}
 {{ end -}}
 `
 
-func initTemplate() *template.Template {
-	tmpl, err := template.New("otelwrap").Parse(templateString)
+var metricsTemplateString = `
+// {{ .Metrics.StructName }} wraps OpenTelemetry's metrics
+type {{ .Metrics.StructName }}{{ .TypeParams }} struct {
+	{{ if .NoEmbed }}{{ .Field }} {{ end }}{{ .Name }}{{ .InterfaceTypeArgs }}
+	requests {{ .Metrics.MetricPkg }}.Int64Counter
+	errors {{ .Metrics.MetricPkg }}.Int64Counter
+	duration {{ .Metrics.MetricPkg }}.Float64Histogram
+}
+{{ if not .TypeParams }}
+var _ {{ .Name }}{{ .InterfaceTypeArgs }} = (*{{ .Metrics.StructName }})(nil)
+{{ end }}
+// New{{ .Metrics.StructName }} creates a metrics wrapper
+func New{{ .Metrics.StructName }}{{ .TypeParams }}(wrapped {{ .Name }}{{ .InterfaceTypeArgs }},
+	{{- " " }}meter {{ .Metrics.MetricPkg }}.Meter, prefix string) (*{{ .Metrics.StructName }}{{ .TypeArgs }}, error) {
+	requests, err := meter.Int64Counter(prefix + "requests")
+	if err != nil {
+		return nil, err
+	}
+	errorCounter, err := meter.Int64Counter(prefix + "errors")
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	duration, err := meter.Float64Histogram(prefix + "duration", {{ .Metrics.MetricPkg }}.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	return &{{ .Metrics.StructName }}{{ .TypeArgs }}{
+		{{ .Field }}: wrapped,
+		requests: requests,
+		errors: errorCounter,
+		duration: duration,
+	}, nil
+}
+{{ if .Unwrap }}
+// Unwrap returns the wrapped implementation
+func (w *{{ .Metrics.StructName }}{{ .TypeArgs }}) Unwrap() {{ .Name }}{{ .InterfaceTypeArgs }} {
+	return w.{{ .Field }}
+}
+{{ end }}
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *{{ .Metrics.StructName }}{{ .TypeArgs }}) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.{{ .Field }}
+}
+
+func (w *{{ .Metrics.StructName }}{{ .TypeArgs }}) record(ctx {{ .Metrics.ContextPkg }}.Context, method string,
+	{{- " " }}start {{ .Metrics.TimePkg }}.Time, failed bool) {
+	attrs := {{ .Metrics.MetricPkg }}.WithAttributes(
+		{{ .Metrics.AttributePkg }}.String("method", method),
+		{{ .Metrics.AttributePkg }}.Bool("error", failed),
+	)
+	w.requests.Add(ctx, 1, attrs)
+	if failed {
+		w.errors.Add(ctx, 1, attrs)
+	}
+	w.duration.Record(ctx, {{ .Metrics.TimePkg }}.Since(start).Seconds(), attrs)
+}
+{{ range .Methods }}
+// {{ .Name }} ...
+func (w *{{ $.Metrics.StructName }}{{ $.TypeArgs }}) {{ .Name }}{{ .ParamsString }}{{ .ResultsString }}{
+	{{- if .Forward }}
+	{{ if .WithReturn }}return {{ end }}w.{{ $.Field }}.{{ .Name }}({{ .ArgsString }})
+	{{- else }}
+	{{ .StartName }} := {{ $.Metrics.TimePkg }}.Now()
+	{{ if .WithReturn -}}
+	{{ .ResultsRecvString }} = w.{{ $.Field }}.{{ .Name }}({{ .ArgsString }})
+	w.record({{ .MetricsCtx }}, "{{ .Name }}", {{ .StartName }},
+		{{- " " }}{{ if .WithError }}{{ .ErrString }} != nil{{ else }}false{{ end }})
+	return {{ .ResultsRecvString }}
+	{{- else -}}
+	w.{{ $.Field }}.{{ .Name }}({{ .ArgsString }})
+	w.record({{ .MetricsCtx }}, "{{ .Name }}", {{ .StartName }}, false)
+	{{- end }}
+	{{- end }}
+}
+{{ end -}}
+`
+
+var funcTemplateString = `
+// {{ .Func.Name }} wraps {{ .Name }}{{ .InterfaceTypeArgs }} with OpenTelemetry's span
+func {{ .Func.Name }}{{ .TypeParams }}({{ .Func.F }} {{ .Name }}{{ .InterfaceTypeArgs }},
+	{{- " " }}{{ .Func.Tracer }} {{ .ChosenOtelTracer }}, {{ .Func.SpanName }} string
+	{{- "" }}) {{ .Name }}{{ .InterfaceTypeArgs }} {
+	{{- with $method := index .Methods 0 }}
+	return func{{ .ParamsString }}{{ .ResultsString }}{
+		{{ .CtxName }}, {{ .SpanName }} := {{ $.Func.Tracer }}.Start({{ .ParentCtx }}, {{ $.Func.SpanName }}
+			{{- with .SpanKind }}, {{ . }}{{ end }})
+		{{- with $.Panics }}
+		defer func() {
+			if r := recover(); r != nil {
+				{{ $method.SpanName }}.RecordError({{ .FmtPkg }}.Errorf("panic: %v", r), {{ .WithStackTrace }}(true))
+				{{ $method.SpanName }}.SetStatus({{ $method.ChosenOtelCodes }}, {{ .FmtPkg }}.Sprint(r))
+				{{ $method.SpanName }}.End()
+				panic(r)
+			}
+			{{ $method.SpanName }}.End()
+		}()
+		{{- else }}
+		defer {{ .SpanName }}.End()
+		{{- end }}
+		{{- with .ContextUpdate }}
+		{{ . }}
+		{{- end }}
+
+		{{ if .WithReturn -}}
+		{{ .ResultsRecvString }} = {{ $.Func.F }}({{ .ArgsString }})
+		{{ if .WithError -}}
+		if {{ .ErrString }} != nil {
+			{{ .SpanName }}.RecordError({{ .ErrString }})
+			{{ .SpanName }}.SetStatus({{ .ChosenOtelCodes }}, {{ .ErrString }}.Error())
+		}
+		{{- end }}
+		return {{ .ResultsRecvString }}
+		{{- else -}}
+		{{ $.Func.F }}({{ .ArgsString }})
+		{{- end }}
 	}
+	{{- end }}
+}
+`
+
+var callbackTemplateString = `if {{ .Name }} != nil {
+		{{ .Original }} := {{ .Name }}
+		{{ .Name }} = func{{ .ParamsString }}{{ .ResultsString }}{
+			{{ .CtxName }}, {{ .SpanName }} := w.tracer.Start({{ .ParentCtx }}, {{ .SpanNameExpr }}{{ .StartOptions }})
+			defer {{ .SpanName }}.End()
+
+			{{ if .WithReturn -}}
+			{{ .ResultsRecvString }} = {{ .Original }}({{ .ArgsString }})
+			{{ if .WithError -}}
+			if {{ .ErrString }} != nil {
+				{{ .SpanName }}.RecordError({{ .ErrString }})
+				{{ .SpanName }}.SetStatus({{ .ChosenOtelCodes }}, {{ .ErrString }}.Error())
+			}
+			{{- end }}
+			return {{ .ResultsRecvString }}
+			{{- else -}}
+			{{ .Original }}({{ .ArgsString }})
+			{{- end }}
+		}
+	}`
+
+func initTemplate() *template.Template {
+	tmpl := template.Must(template.New("otelwrap").Funcs(templateFuncs).Parse(templateString))
+	template.Must(tmpl.New("metrics").Parse(metricsTemplateString))
+	template.Must(tmpl.New("func").Parse(funcTemplateString))
+	template.Must(tmpl.New("callback").Parse(callbackTemplateString))
 	return tmpl
 }
 
 var resultTemplate = initTemplate()
 
 type templateMethod struct {
+	Index    int // index in the list of traced methods
 	Name     string
+	Forward  bool // only calls the wrapped implementation, for methods that are not traced
 	CtxName  string
 	SpanName string
 
+	ParentCtx     string // parent context of the span
+	MetricsCtx    string // context used for recording metrics
+	ContextUpdate string // statement passing the context of the span to the wrapped method, if needed
+
 	ParamsString  string
 	ResultsString string
 	ArgsString    string
@@ -78,14 +436,131 @@ type templateMethod struct {
 	ResultsRecvString string
 	ErrString         string
 	ChosenOtelCodes   string
+
+	Attributes       []string
+	ResultAttributes []string // attributes of the results, set after calling the wrapped method
+
+	Stream *templateStream // the span ends when the streamed result is consumed
+
+	Link            string             // statement creating the link to the span for callbacks with a context
+	Callbacks       []templateCallback // func-typed params, wrapped before calling the wrapped method
+	ResultCallbacks []templateCallback // func-typed results, wrapped before returning
+
+	StartName string
+
+	SpanKind     string // span kind option, empty if not specified
+	StartOptions string // field of the start options in the options-based constructor
+
+	// only used by custom templates
+	Params     []templateParam
+	Results    []templateParam
+	Directives []templateDirective
+}
+
+type templateStream struct {
+	Var    string // variable indicating that the span is ended by the streamed result
+	Result string // name of the streamed result
+	Wrap   string // expression wrapping the result
+}
+
+type templateMetrics struct {
+	StructName string
+
+	MetricPkg    string
+	AttributePkg string
+	ContextPkg   string
+	TimePkg      string
+}
+
+type templatePanics struct {
+	FmtPkg         string
+	WithStackTrace string
+}
+
+type templateClassifier struct {
+	SupportPkg string
+	Param      bool   // the constructor accepts a classifier
+	Default    string // expression of the classifier used when not specified
+}
+
+type templateKindStartOptions struct {
+	Field    string
+	SpanKind string
+}
+
+type templateOptions struct {
+	SupportPkg      string
+	SpanStartOption string
+	ScopeName       string // instrumentation scope name of the tracer, the package path of the interface
+
+	KindStartOptions []templateKindStartOptions // start options with span kinds, precomputed in the constructor
+}
+
+// startOptionsField returns the field of the start options for the span kind
+func (o *templateOptions) startOptionsField(kind string, spanKindOption string) string {
+	if kind == "" {
+		return "startOptions"
+	}
+
+	field := kind + "StartOptions"
+	for _, existing := range o.KindStartOptions {
+		if existing.Field == field {
+			return field
+		}
+	}
+	o.KindStartOptions = append(o.KindStartOptions, templateKindStartOptions{
+		Field:    field,
+		SpanKind: spanKindOption,
+	})
+	return field
+}
+
+type templateCode struct {
+	KeyValue string
+	Methods  [][]string // code attributes of each traced method
+}
+
+type templateStruct struct {
+	Name    string   // struct type implementing the synthesized interface
+	Methods []string // method specs of the synthesized interface
+}
+
+type templateFunc struct {
+	Name string // name of the wrapping function
+
+	// params of the wrapping function
+	F        string
+	Tracer   string
+	SpanName string
+}
+
+type templateFallback struct {
+	ContextPkg string
 }
 
 type templateInterface struct {
 	Name             string
 	UsedName         string
 	StructName       string
+	NoEmbed          bool   // stores the implementation in a private field instead of embedding it
+	Field            string // field holding the implementation
+	Unwrap           bool   // generates the Unwrap method, false if the interface has a method with the same name
 	Methods          []templateMethod
+	SkippedMethods   []string // names of the methods not traced because of the skip directive or option
 	ChosenOtelTracer string
+
+	TypeParams        string // type parameter list of a generic interface, e.g. [T any]
+	TypeArgs          string // type arguments of the generated structs, e.g. [T]
+	InterfaceTypeArgs string // type arguments of the wrapped interface, e.g. [T] or [User]
+
+	Options    *templateOptions // options-based constructor
+	Metrics    *templateMetrics
+	Panics     *templatePanics
+	Classifier *templateClassifier
+	Code       *templateCode
+	Fallback   *templateFallback // fallback context for methods without a context
+	Struct     *templateStruct   // interface synthesized from the methods of a struct type
+	Func       *templateFunc     // wrapping function of a named func type, instead of a struct
 }
 
 type templatePackageInfo struct {
@@ -119,6 +594,12 @@ func collectVariables(info packageTypeInfo) templateVariables {
 	interfaces := make([]templateInterfaceVariables, 0, len(info.interfaces))
 	for _, interfaceDetail := range info.interfaces {
 		global[interfaceDetail.name] = struct{}{}
+		if interfaceDetail.structName != "" {
+			global[interfaceDetail.structName] = struct{}{}
+		}
+		for _, typeParam := range interfaceDetail.typeParams {
+			global[typeParam.name] = struct{}{}
+		}
 
 		var methods []templateMethodVariables
 		for _, method := range interfaceDetail.methods {
@@ -177,7 +658,12 @@ func assignVariableNamesForMethod(
 	local map[string]recognizedType,
 	method methodType,
 ) {
-	assignVariableNamesForFields(global, local, method.params, 1)
+	// unnamed params are named a, b, c, ... not counting the leading context
+	startPosition := 0
+	if len(method.params) > 0 && method.params[0].recognized == recognizedTypeContext {
+		startPosition = 1
+	}
+	assignVariableNamesForFields(global, local, method.params, startPosition)
 	assignVariableNamesForFields(global, local, method.results, 0)
 }
 
@@ -218,6 +704,14 @@ func getVariableName(
 		recommendedName = fmt.Sprintf("%c", ch)
 	}
 
+	return chooseVariableName(global, local, recommendedName)
+}
+
+func chooseVariableName(
+	global map[string]struct{},
+	local map[string]recognizedType,
+	recommendedName string,
+) string {
 	for retryIndex := 0; ; retryIndex++ {
 		name := getNextVariableName(recommendedName, retryIndex)
 		if _, existed := global[name]; existed {
@@ -267,6 +761,27 @@ func generateFieldListString(fields []tupleType, importController *importer) str
 	return strings.Join(fieldList, ", ")
 }
 
+func generateTypeParamsString(typeParams []tupleType, importController *importer) string {
+	if len(typeParams) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("[%s]", generateFieldListString(typeParams, importController))
+}
+
+func generateTypeArgsString(typeParams []tupleType, typeArgs []tupleType, importController *importer) string {
+	var args []string
+	for _, typeParam := range typeParams {
+		args = append(args, typeParam.name)
+	}
+	for _, typeArg := range typeArgs {
+		args = append(args, replacePackageName(typeArg.typeStr, typeArg.pkgList, importController))
+	}
+	if len(args) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("[%s]", strings.Join(args, ", "))
+}
+
 func generateArgsString(fields []tupleType) string {
 	var args []string
 	for _, field := range fields {
@@ -280,34 +795,100 @@ func generateArgsString(fields []tupleType) string {
 }
 
 const (
-	otelTracePkgPath = "go.opentelemetry.io/otel/trace"
-	otelCodesPkgPath = "go.opentelemetry.io/otel/codes"
+	otelTracePkgPath     = "go.opentelemetry.io/otel/trace"
+	otelCodesPkgPath     = "go.opentelemetry.io/otel/codes"
+	otelAttributePkgPath = "go.opentelemetry.io/otel/attribute"
 )
 
-func generateCodeForMethod(
-	global map[string]struct{},
-	local map[string]recognizedType,
-	method methodType,
-	importController *importer,
-) templateMethod {
-	paramsStr := generateFieldListString(method.params, importController)
-	paramsStr = fmt.Sprintf("(%s)", paramsStr)
+func attributePkgList() []tupleTypePkg {
+	return []tupleTypePkg{
+		{
+			path:  otelAttributePkgPath,
+			begin: 0,
+			end:   len("attribute"),
+		},
+	}
+}
 
-	ctxName := ""
-	for _, param := range method.params {
-		if param.recognized == recognizedTypeContext {
-			ctxName = param.name
-			break
+func generateAttributesString(attributes []spanAttribute, tuples []tupleType, importController *importer) []string {
+	var result []string
+	for _, attr := range attributes {
+		constructor := replacePackageName("attribute."+attr.constructor, attributePkgList(), importController)
+
+		value := tuples[attr.index].name
+		if attr.length {
+			value = fmt.Sprintf("len(%s)", value)
+		}
+		if attr.conversion != "" {
+			value = fmt.Sprintf("%s(%s)", attr.conversion, value)
 		}
+		result = append(result, fmt.Sprintf("%s(%q, %s)", constructor, attr.key, value))
+	}
+	return result
+}
+
+//revive:disable-next-line:flag-parameter
+func generateCodeAttributesString(
+	namespace string, method methodType, withLocation bool, importController *importer,
+) []string {
+	attribute := func(constructor string, key string, value any) string {
+		constructor = replacePackageName("attribute."+constructor, attributePkgList(), importController)
+		return fmt.Sprintf("%s(%q, %#v)", constructor, key, value)
+	}
+
+	result := []string{
+		attribute("String", "code.function", method.name),
+		attribute("String", "code.namespace", namespace),
 	}
+	if withLocation {
+		result = append(result,
+			attribute("String", "code.filepath", method.location.filePath),
+			attribute("Int", "code.lineno", method.location.line),
+		)
+	}
+	return result
+}
+
+func generateSignatureStrings(method methodType, importController *importer) (paramsStr string, resultsStr string) {
+	paramsStr = generateFieldListString(method.params, importController)
+	paramsStr = fmt.Sprintf("(%s)", paramsStr)
 
-	var resultsStr string
 	if len(method.results) == 0 {
 		resultsStr = " "
 	} else {
 		resultsStr = generateFieldListString(method.results, importController)
 		resultsStr = fmt.Sprintf(" (%s) ", resultsStr)
 	}
+	return paramsStr, resultsStr
+}
+
+// generateForwardMethod generates a method that only calls the wrapped implementation
+func generateForwardMethod(method methodType, importController *importer) templateMethod {
+	paramsStr, resultsStr := generateSignatureStrings(method, importController)
+	return templateMethod{
+		Name:    method.name,
+		Forward: true,
+
+		ParamsString:  paramsStr,
+		ResultsString: resultsStr,
+		ArgsString:    generateArgsString(method.params),
+
+		WithReturn: resultsStr != " ",
+
+		Params:     newTemplateParams(method.params, importController),
+		Results:    newTemplateParams(method.results, importController),
+		Directives: newTemplateDirectives(method.directives),
+	}
+}
+
+func generateCodeForMethod(
+	global map[string]struct{},
+	local map[string]recognizedType,
+	method methodType,
+	methodCtx methodContext,
+	importController *importer,
+) templateMethod {
+	paramsStr, resultsStr := generateSignatureStrings(method, importController)
 
 	errStr := ""
 	var recvVars []string
@@ -318,13 +899,42 @@ func generateCodeForMethod(
 		}
 	}
 
+	for _, result := range method.results {
+		local[result.name] = result.recognized
+	}
+
+	var ctxName, parentCtx, metricsCtx, contextUpdate string
+	switch methodCtx.strategy {
+	case contextStrategyRequest:
+		requestName := method.params[methodCtx.paramIndex].name
+		ctxName = getVariableName(global, local, 0, recognizedTypeContext)
+		local[ctxName] = recognizedTypeContext
+
+		parentCtx = requestName + ".Context()"
+		metricsCtx = parentCtx
+		contextUpdate = fmt.Sprintf("%s = %s.WithContext(%s)", requestName, requestName, ctxName)
+	case contextStrategyWrapper:
+		ctxName = "_"
+		parentCtx = "w.fallbackCtx"
+		metricsCtx = importController.chosenName("context") + ".Background()"
+	default:
+		ctxName = method.params[methodCtx.paramIndex].name
+		parentCtx = ctxName
+		metricsCtx = ctxName
+	}
+
 	spanName := getVariableName(global, local, 0, recognizedTypeSpan)
+	startName := chooseVariableName(global, local, "start")
 
 	return templateMethod{
 		Name:     method.name,
 		CtxName:  ctxName,
 		SpanName: spanName,
 
+		ParentCtx:     parentCtx,
+		MetricsCtx:    metricsCtx,
+		ContextUpdate: contextUpdate,
+
 		ParamsString:  paramsStr,
 		ResultsString: resultsStr,
 		ArgsString:    generateArgsString(method.params),
@@ -340,6 +950,15 @@ func generateCodeForMethod(
 				end:   len("codes"),
 			},
 		}, importController),
+
+		Attributes:       generateAttributesString(method.attributes, method.params, importController),
+		ResultAttributes: generateAttributesString(method.resultAttributes, method.results, importController),
+
+		StartName: startName,
+
+		Params:     newTemplateParams(method.params, importController),
+		Results:    newTemplateParams(method.results, importController),
+		Directives: newTemplateDirectives(method.directives),
 	}
 }
 
@@ -365,6 +984,28 @@ func importControllerAddImports(importController *importer, imports []importInfo
 type generateConfig struct {
 	inAnotherPackage bool
 	pkgName          string
+
+	withMetrics     bool
+	recordPanics    bool
+	errorClassifier bool
+	ignoreErrors    []string
+	withOptions     bool
+	codeAttributes  bool
+	codeLocation    bool
+	spanKind        string
+	allMethods      bool
+	noEmbed         bool
+	streaming       bool
+	callbacks       bool
+	template        string // text of a custom template, empty for the built-in template
+	skipMethods     []string
+	match           string // regular expression selecting the interfaces of the package
+	exclude         string // regular expression excluding the interfaces selected by patterns
+	matchReport     io.Writer
+	report          io.Writer
+	diagnostics     func(d Diagnostic)
+	typeArgs        map[string][]string
+	namePrefix      string
 }
 
 // Option ...
@@ -378,94 +1019,677 @@ func WithInAnotherPackage(packageName string) Option {
 	}
 }
 
-func computeGenerateConfig(options ...Option) generateConfig {
-	conf := generateConfig{
-		inAnotherPackage: false,
+// WithMetrics also generates a wrapper recording metrics for each interface
+func WithMetrics() Option {
+	return func(conf *generateConfig) {
+		conf.withMetrics = true
 	}
-	for _, o := range options {
-		o(&conf)
+}
+
+// WithRecordPanics records panics of the wrapped methods as errors of the spans before re-panicking
+func WithRecordPanics() Option {
+	return func(conf *generateConfig) {
+		conf.recordPanics = true
 	}
-	return conf
 }
 
-func containsErrorReturns(info packageTypeInfo) bool {
-	for _, interfaceDetail := range info.interfaces {
-		for _, method := range interfaceDetail.methods {
-			for _, result := range method.results {
-				if result.recognized == recognizedTypeError {
-					return true
-				}
-			}
-		}
+// WithOptionsConstructor generates constructors accepting options of the package
+// github.com/QuangTung97/otelwrap/support instead of a tracer and a prefix
+func WithOptionsConstructor() Option {
+	return func(conf *generateConfig) {
+		conf.withOptions = true
 	}
-	return false
 }
 
-func generateCode(writer io.Writer, info packageTypeInfo, options ...Option) error {
-	conf := computeGenerateConfig(options...)
+// WithErrorClassifier adds a classifier of errors to the constructors of the wrappers
+func WithErrorClassifier() Option {
+	return func(conf *generateConfig) {
+		conf.errorClassifier = true
+	}
+}
 
-	importController := newImporter()
-	if conf.inAnotherPackage {
-		importController.add(importInfo{
-			path: info.path,
-			name: path.Base(info.path),
-		})
+// WithIgnoreErrors ignores errors matching the sentinel errors, referenced as import/path.Name,
+// e.g. database/sql.ErrNoRows
+func WithIgnoreErrors(refs ...string) Option {
+	return func(conf *generateConfig) {
+		conf.ignoreErrors = append(conf.ignoreErrors, refs...)
 	}
-	addOtelCodes := containsErrorReturns(info)
-	importControllerAddImports(importController, info.imports, addOtelCodes)
+}
 
-	controllerImports := importController.getImports()
-	newImports := make([]importInfo, 0, len(controllerImports))
-	for _, clause := range controllerImports {
-		newImports = append(newImports, importInfo{
-			name: clause.usedName,
-			path: clause.path,
-		})
+// WithCodeAttributes adds the code.function and code.namespace attributes to every span
+func WithCodeAttributes() Option {
+	return func(conf *generateConfig) {
+		conf.codeAttributes = true
 	}
-	info.imports = newImports
+}
 
-	variables := collectVariables(info)
-	info = assignVariableNames(info)
+// WithCodeLocation adds the code.filepath and code.lineno attributes of the method declarations
+// to every span, in addition to the attributes added by WithCodeAttributes
+func WithCodeLocation() Option {
+	return func(conf *generateConfig) {
+		conf.codeAttributes = true
+		conf.codeLocation = true
+	}
+}
 
-	global := variables.globalVariables
+// WithSpanKind specifies the span kind of methods without the kind directive,
+// one of internal, server, client, producer or consumer
+func WithSpanKind(kind string) Option {
+	return func(conf *generateConfig) {
+		conf.spanKind = kind
+	}
+}
 
-	var interfaces []templateInterface
-	for interfaceIndex, interfaceDetail := range info.interfaces {
-		var methods []templateMethod
-		for methodIndex, method := range interfaceDetail.methods {
-			if len(method.params) == 0 || method.params[0].recognized != recognizedTypeContext {
-				continue
-			}
-			local := variables.interfaces[interfaceIndex].methods[methodIndex].variables
-			methods = append(methods, generateCodeForMethod(global, local, method, importController))
-		}
+// WithAllMethods also traces methods without context.Context as the first param,
+// using a context param at any position, the context of a *http.Request param,
+// or the fallback context given to the constructor of the wrapper
+func WithAllMethods() Option {
+	return func(conf *generateConfig) {
+		conf.allMethods = true
+	}
+}
 
-		embeddedInterfaceName := replacePackageName(interfaceDetail.name,
-			[]tupleTypePkg{
-				{
-					path:  info.path,
-					begin: 0,
-					end:   0,
-				},
-			},
-			importController,
-		)
-		interfaces = append(interfaces, templateInterface{
-			Name:       embeddedInterfaceName,
-			UsedName:   interfaceDetail.name,
-			StructName: interfaceDetail.name + "Wrapper",
-			Methods:    methods,
-			ChosenOtelTracer: replacePackageName("trace.Tracer", []tupleTypePkg{
-				{
-					path:  otelTracePkgPath,
-					begin: 0,
-					end:   len("trace"),
-				},
-			}, importController),
-		})
+// WithNoEmbed stores the implementation in a private field instead of embedding the interface,
+// generating forwarding methods for the methods that are not traced
+func WithNoEmbed() Option {
+	return func(conf *generateConfig) {
+		conf.noEmbed = true
 	}
+}
 
-	var importStmts []string
+// WithStreaming ends the spans of methods returning io.Reader, io.ReadCloser, <-chan T or iter.Seq[T]
+// when the results are consumed instead of when the methods return
+func WithStreaming() Option {
+	return func(conf *generateConfig) {
+		conf.streaming = true
+	}
+}
+
+// WithCallbacks wraps func-typed params and results of the traced methods,
+// so that their invocations run in child spans linked to the spans of the methods
+func WithCallbacks() Option {
+	return func(conf *generateConfig) {
+		conf.callbacks = true
+	}
+}
+
+// WithTemplate generates the code with a custom template instead of the built-in template,
+// see DefaultTemplate for the data passed to the template
+func WithTemplate(text string) Option {
+	return func(conf *generateConfig) {
+		conf.template = text
+	}
+}
+
+// WithMatch also selects the interfaces with names matching the regular expression,
+// for LoadAndGenerateGroups only in the package of the first group
+func WithMatch(expr string) Option {
+	return func(conf *generateConfig) {
+		conf.match = expr
+	}
+}
+
+// WithExclude excludes the interfaces with names matching the regular expression
+// from the interfaces selected by glob patterns, e.g. Repo*, or by WithMatch
+func WithExclude(expr string) Option {
+	return func(conf *generateConfig) {
+		conf.exclude = expr
+	}
+}
+
+// WithMatchReport writes the interfaces selected by glob patterns or by WithMatch to w
+func WithMatchReport(w io.Writer) Option {
+	return func(conf *generateConfig) {
+		conf.matchReport = w
+	}
+}
+
+// WithSkipMethods does not trace the methods with the names, of the form Method or Interface.Method,
+// the same as the skip directive
+func WithSkipMethods(names ...string) Option {
+	return func(conf *generateConfig) {
+		conf.skipMethods = append(conf.skipMethods, names...)
+	}
+}
+
+// WithContextReport writes how the context of each method is obtained to w
+func WithContextReport(w io.Writer) Option {
+	return func(conf *generateConfig) {
+		conf.report = w
+	}
+}
+
+// WithDiagnostics calls fn for each method that is not traced or is traced without a context param
+func WithDiagnostics(fn func(d Diagnostic)) Option {
+	return func(conf *generateConfig) {
+		conf.diagnostics = fn
+	}
+}
+
+// WithTypeArgs generates a wrapper for the instantiation of a generic interface
+func WithTypeArgs(interfaceName string, typeArgs ...string) Option {
+	return func(conf *generateConfig) {
+		if conf.typeArgs == nil {
+			conf.typeArgs = map[string][]string{}
+		}
+		conf.typeArgs[interfaceName] = typeArgs
+	}
+}
+
+// WithNamePrefix adds a prefix to the names of generated structs
+func WithNamePrefix(prefix string) Option {
+	return func(conf *generateConfig) {
+		conf.namePrefix = prefix
+	}
+}
+
+func computeGenerateConfig(options ...Option) generateConfig {
+	conf := generateConfig{
+		inAnotherPackage: false,
+	}
+	for _, o := range options {
+		o(&conf)
+	}
+	return conf
+}
+
+//revive:disable-next-line:flag-parameter
+func containsErrorReturns(info packageTypeInfo, allMethods bool) bool {
+	for _, interfaceDetail := range info.interfaces {
+		for _, method := range interfaceDetail.methods {
+			if findMethodContext(method, allMethods).strategy == contextStrategyNone {
+				continue
+			}
+			for _, result := range method.results {
+				if result.recognized == recognizedTypeError {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isMethodRendered returns whether the signature of the method appears in the generated code,
+// methods that are not traced fall through to the embedded interface unless they are forwarded
+func isMethodRendered(interfaceDetail interfaceInfo, method methodType, conf generateConfig) bool {
+	if interfaceDetail.funcType || interfaceDetail.structName != "" || conf.noEmbed {
+		return true
+	}
+	return findMethodContext(method, conf.allMethods).strategy != contextStrategyNone
+}
+
+func addTuplePkgPaths(paths map[string]bool, tuples []tupleType) {
+	for _, tuple := range tuples {
+		for _, pkg := range tuple.pkgList {
+			paths[pkg.path] = true
+		}
+	}
+}
+
+// renderedImports removes the imports only used by the signatures of methods that are not rendered
+func renderedImports(info packageTypeInfo, conf generateConfig) []importInfo {
+	used := map[string]bool{}
+	unused := map[string]bool{}
+	for _, interfaceDetail := range info.interfaces {
+		addTuplePkgPaths(used, interfaceDetail.typeParams)
+		addTuplePkgPaths(used, interfaceDetail.typeArgs)
+		for _, method := range interfaceDetail.methods {
+			paths := unused
+			if isMethodRendered(interfaceDetail, method, conf) {
+				paths = used
+			}
+			addTuplePkgPaths(paths, method.params)
+			addTuplePkgPaths(paths, method.results)
+		}
+	}
+
+	result := make([]importInfo, 0, len(info.imports))
+	for _, importDetail := range info.imports {
+		if unused[importDetail.path] && !used[importDetail.path] {
+			continue
+		}
+		result = append(result, importDetail)
+	}
+	return result
+}
+
+const (
+	otelMetricPkgPath = "go.opentelemetry.io/otel/metric"
+)
+
+func importControllerAddMetricsImports(importController *importer) {
+	importController.add(importInfo{
+		path: "context",
+		name: "context",
+	})
+	importController.add(importInfo{
+		path: "time",
+		name: "time",
+	})
+	importController.add(importInfo{
+		path: otelMetricPkgPath,
+		name: "metric",
+	}, withPreferPrefix("otel"))
+	importController.add(importInfo{
+		path: otelAttributePkgPath,
+		name: "attribute",
+	}, withPreferPrefix("otel"))
+}
+
+func newTemplateMetrics(structName string, importController *importer) *templateMetrics {
+	return &templateMetrics{
+		StructName: structName,
+
+		MetricPkg:    importController.chosenName(otelMetricPkgPath),
+		AttributePkg: importController.chosenName(otelAttributePkgPath),
+		ContextPkg:   importController.chosenName("context"),
+		TimePkg:      importController.chosenName("time"),
+	}
+}
+
+func newTemplatePanics(importController *importer) *templatePanics {
+	return &templatePanics{
+		FmtPkg: importController.chosenName("fmt"),
+		WithStackTrace: replacePackageName("trace.WithStackTrace", []tupleTypePkg{
+			{
+				path:  otelTracePkgPath,
+				begin: 0,
+				end:   len("trace"),
+			},
+		}, importController),
+	}
+}
+
+//revive:disable-next-line:flag-parameter
+func importControllerAddClassifierImports(importController *importer, info packageTypeInfo, inAnotherPackage bool) {
+	importController.add(importInfo{
+		path: supportPkgPath,
+		name: "support",
+	}, withPreferPrefix("otelwrap"))
+	for _, sentinel := range info.sentinels {
+		if sentinel.pkg.path == info.path && !inAnotherPackage {
+			continue
+		}
+		importController.add(sentinel.pkg)
+	}
+}
+
+//revive:disable-next-line:flag-parameter
+func newTemplateClassifier(param bool, sentinels []sentinelError, importController *importer) *templateClassifier {
+	supportPkg := importController.chosenName(supportPkgPath)
+
+	defaultClassifier := supportPkg + ".DefaultErrorClassifier"
+	if len(sentinels) > 0 {
+		args := []string{defaultClassifier}
+		for _, sentinel := range sentinels {
+			args = append(args, replacePackageName(sentinel.name, []tupleTypePkg{
+				{
+					path:  sentinel.pkg.path,
+					begin: 0,
+					end:   0,
+				},
+			}, importController))
+		}
+		defaultClassifier = fmt.Sprintf("%s.IgnoreErrors(%s)", supportPkg, strings.Join(args, ", "))
+	}
+
+	return &templateClassifier{
+		SupportPkg: supportPkg,
+		Param:      param,
+		Default:    defaultClassifier,
+	}
+}
+
+func newTemplateOptions(scopeName string, importController *importer) *templateOptions {
+	return &templateOptions{
+		SupportPkg: importController.chosenName(supportPkgPath),
+		SpanStartOption: replacePackageName("trace.SpanStartOption", []tupleTypePkg{
+			{
+				path:  otelTracePkgPath,
+				begin: 0,
+				end:   len("trace"),
+			},
+		}, importController),
+		ScopeName: scopeName,
+	}
+}
+
+func resolveSpanKind(conf generateConfig, interfaceDetail interfaceInfo, method methodType) string {
+	if method.spanKind != "" {
+		return method.spanKind
+	}
+	if interfaceDetail.spanKind != "" {
+		return interfaceDetail.spanKind
+	}
+	return conf.spanKind
+}
+
+func generateSpanKindString(kind string, importController *importer) string {
+	expr := fmt.Sprintf("trace.WithSpanKind(trace.%s)", spanKindConstants[kind])
+	return replacePackageName(expr, []tupleTypePkg{
+		{
+			path:  otelTracePkgPath,
+			begin: 0,
+			end:   len("trace"),
+		},
+		{
+			path:  otelTracePkgPath,
+			begin: len("trace.WithSpanKind("),
+			end:   len("trace.WithSpanKind(trace"),
+		},
+	}, importController)
+}
+
+//revive:disable-next-line:flag-parameter
+func containsAttributes(info packageTypeInfo, allMethods bool) bool {
+	for _, interfaceDetail := range info.interfaces {
+		for _, method := range interfaceDetail.methods {
+			traced := findMethodContext(method, allMethods).strategy != contextStrategyNone
+			if traced && (len(method.attributes) > 0 || len(method.resultAttributes) > 0) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//revive:disable-next-line:flag-parameter
+func usesFallbackContext(interfaceDetail interfaceInfo, allMethods bool) bool {
+	for _, method := range interfaceDetail.methods {
+		if findMethodContext(method, allMethods).strategy == contextStrategyWrapper {
+			return true
+		}
+	}
+	return false
+}
+
+func newTemplateStruct(pkgPath string, interfaceDetail interfaceInfo, importController *importer) *templateStruct {
+	var methods []string
+	for _, method := range interfaceDetail.methods {
+		paramsStr, resultsStr := generateSignatureStrings(method, importController)
+		methods = append(methods, method.name+paramsStr+strings.TrimRight(resultsStr, " "))
+	}
+	return &templateStruct{
+		Name:    qualifiedTypeName(interfaceDetail.structName, pkgPath, importController),
+		Methods: methods,
+	}
+}
+
+// streamWrapFunctions are the functions of the support package wrapping streamed results
+var streamWrapFunctions = map[recognizedType]string{
+	recognizedTypeReader:     "TraceReader",
+	recognizedTypeReadCloser: "TraceReadCloser",
+	recognizedTypeRecvChan:   "TraceChan",
+	recognizedTypeSeq:        "TraceSeq",
+}
+
+// findStreamResult returns the index of the first result that can be streamed, -1 if not found
+func findStreamResult(method methodType) int {
+	for i, result := range method.results {
+		if _, ok := streamWrapFunctions[result.recognized]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+func containsStreamResults(info packageTypeInfo, allMethods bool) bool {
+	for _, interfaceDetail := range info.interfaces {
+		if interfaceDetail.funcType {
+			continue
+		}
+		for _, method := range interfaceDetail.methods {
+			traced := findMethodContext(method, allMethods).strategy != contextStrategyNone
+			if traced && findStreamResult(method) >= 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newTemplateStream(
+	global map[string]struct{},
+	local map[string]recognizedType,
+	method methodType,
+	spanName string,
+	importController *importer,
+) *templateStream {
+	index := findStreamResult(method)
+	if index < 0 {
+		return nil
+	}
+	result := method.results[index]
+
+	varName := chooseVariableName(global, local, "streaming")
+	local[varName] = recognizedTypeUnknown
+
+	supportPkg := importController.chosenName(supportPkgPath)
+	return &templateStream{
+		Var:    varName,
+		Result: result.name,
+		Wrap: fmt.Sprintf("%s.%s(%s, %s)",
+			supportPkg, streamWrapFunctions[result.recognized], spanName, result.name),
+	}
+}
+
+// qualifiedTypeName returns the name of a type of the package, qualified if the package is imported
+func qualifiedTypeName(name string, pkgPath string, importController *importer) string {
+	return replacePackageName(name, []tupleTypePkg{
+		{
+			path:  pkgPath,
+			begin: 0,
+			end:   0,
+		},
+	}, importController)
+}
+
+func chosenOtelTracer(importController *importer) string {
+	return replacePackageName("trace.Tracer", []tupleTypePkg{
+		{
+			path:  otelTracePkgPath,
+			begin: 0,
+			end:   len("trace"),
+		},
+	}, importController)
+}
+
+// containsWrappers returns true if there is an interface wrapped by a struct, i.e. not a func type
+func containsWrappers(info packageTypeInfo) bool {
+	for _, interfaceDetail := range info.interfaces {
+		if !interfaceDetail.funcType {
+			return true
+		}
+	}
+	return false
+}
+
+// generateCodeForFunc generates the function wrapping a named func type
+func generateCodeForFunc(
+	conf generateConfig,
+	global map[string]struct{},
+	local map[string]recognizedType,
+	pkgPath string,
+	interfaceDetail interfaceInfo,
+	importController *importer,
+) (templateInterface, error) {
+	method := interfaceDetail.methods[0]
+	methodCtx := findMethodContext(method, conf.allMethods)
+	if methodCtx.strategy == contextStrategyNone || methodCtx.strategy == contextStrategyWrapper {
+		return templateInterface{}, &Error{
+			Stage:     StageGenerate,
+			Interface: interfaceDetail.name,
+			Err:       fmt.Errorf("func type '%s' has no context param", interfaceDetail.name),
+		}
+	}
+
+	generated := generateCodeForMethod(global, local, method, methodCtx, importController)
+	if kind := resolveSpanKind(conf, interfaceDetail, method); kind != "" {
+		generated.SpanKind = generateSpanKindString(kind, importController)
+	}
+	local[generated.SpanName] = recognizedTypeSpan
+
+	// the params of the wrapping function must not be shadowed by the params of the func type
+	chooseName := func(recommendedName string) string {
+		name := chooseVariableName(global, local, recommendedName)
+		local[name] = recognizedTypeUnknown
+		return name
+	}
+
+	var panics *templatePanics
+	if conf.recordPanics {
+		panics = newTemplatePanics(importController)
+	}
+
+	return templateInterface{
+		Name:             qualifiedTypeName(interfaceDetail.name, pkgPath, importController),
+		UsedName:         interfaceDetail.name,
+		Methods:          []templateMethod{generated},
+		ChosenOtelTracer: chosenOtelTracer(importController),
+
+		TypeParams: generateTypeParamsString(interfaceDetail.typeParams, importController),
+		InterfaceTypeArgs: generateTypeArgsString(
+			interfaceDetail.typeParams, interfaceDetail.typeArgs, importController,
+		),
+
+		Panics: panics,
+		Func: &templateFunc{
+			Name:     "Wrap" + conf.namePrefix + interfaceDetail.name,
+			F:        chooseName("f"),
+			Tracer:   chooseName("tracer"),
+			SpanName: chooseName("name"),
+		},
+	}, nil
+}
+
+const unwrapMethodName = "Unwrap"
+
+func hasMethod(interfaceDetail interfaceInfo, name string) bool {
+	for _, method := range interfaceDetail.methods {
+		if method.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// loadTemplate checks the options used by the template and returns the template, the built-in one if not customized
+func loadTemplate(conf generateConfig) (*template.Template, error) {
+	if conf.spanKind != "" {
+		if err := checkSpanKind(conf.spanKind); err != nil {
+			return nil, WrapError(err, StageArgs, "")
+		}
+	}
+	if conf.template == "" {
+		return resultTemplate, nil
+	}
+	custom, err := parseCustomTemplate(conf.template)
+	if err != nil {
+		return nil, WrapError(err, StageArgs, "")
+	}
+	return custom, nil
+}
+
+// addPackageImports adds the packages of the wrapped interfaces and of the rendered signatures
+func addPackageImports(importController *importer, info packageTypeInfo, conf generateConfig) {
+	if conf.inAnotherPackage {
+		importController.add(importInfo{
+			path: info.path,
+			name: info.name,
+		})
+	}
+	for _, interfaceDetail := range info.interfaces {
+		if interfaceDetail.pkg.path != "" {
+			importController.add(interfaceDetail.pkg)
+		}
+	}
+	addOtelCodes := containsErrorReturns(info, conf.allMethods) || conf.recordPanics ||
+		(conf.callbacks && containsCallbackErrors(info, conf.allMethods))
+	importControllerAddImports(importController, renderedImports(info, conf), addOtelCodes)
+}
+
+func usesSupportPackage(info packageTypeInfo, conf generateConfig) bool {
+	if containsWrappers(info) && conf.withOptions {
+		return true
+	}
+	return conf.streaming && containsStreamResults(info, conf.allMethods)
+}
+
+func usesAttributePackage(info packageTypeInfo, conf generateConfig) bool {
+	return containsAttributes(info, conf.allMethods) || (containsWrappers(info) && conf.codeAttributes)
+}
+
+// addFeatureImports adds the packages used by the enabled features of the wrappers
+//
+//revive:disable-next-line:flag-parameter
+func addFeatureImports(importController *importer, info packageTypeInfo, conf generateConfig, withClassifier bool) {
+	if conf.recordPanics {
+		importController.add(importInfo{
+			path: "fmt",
+			name: "fmt",
+		})
+	}
+	if withClassifier {
+		importControllerAddClassifierImports(importController, info, conf.inAnotherPackage)
+	}
+	if usesSupportPackage(info, conf) {
+		importController.add(importInfo{
+			path: supportPkgPath,
+			name: "support",
+		}, withPreferPrefix("otelwrap"))
+	}
+	if usesAttributePackage(info, conf) {
+		importController.add(importInfo{
+			path: otelAttributePkgPath,
+			name: "attribute",
+		}, withPreferPrefix("otel"))
+	}
+	if conf.withMetrics && containsWrappers(info) {
+		importControllerAddMetricsImports(importController)
+	}
+}
+
+// addFallbackContextImport adds the context package if a wrapper uses a fallback context
+func addFallbackContextImport(importController *importer, info packageTypeInfo, conf generateConfig) {
+	for _, interfaceDetail := range info.interfaces {
+		if usesFallbackContext(interfaceDetail, conf.allMethods) {
+			importController.add(importInfo{
+				path: "context",
+				name: "context",
+			})
+		}
+	}
+}
+
+// chosenImports returns the imports with the names chosen by the import controller
+func chosenImports(importController *importer) []importInfo {
+	controllerImports := importController.getImports()
+	result := make([]importInfo, 0, len(controllerImports))
+	for _, clause := range controllerImports {
+		result = append(result, importInfo{
+			name: clause.usedName,
+			path: clause.path,
+		})
+	}
+	return result
+}
+
+// reportMethods writes the context report and the diagnostics of the methods if requested
+func reportMethods(info packageTypeInfo, conf generateConfig) {
+	if conf.report != nil {
+		writeContextReport(conf.report, info, conf.allMethods)
+	}
+	if conf.diagnostics != nil {
+		for _, d := range findDiagnostics(info, conf.allMethods) {
+			conf.diagnostics(d)
+		}
+	}
+}
+
+// importStatements returns the import statements of the chosen imports
+func importStatements(importController *importer) []string {
+	var importStmts []string
 	for _, clause := range importController.getImports() {
 		if clause.aliasName == "" {
 			importStmts = append(importStmts, fmt.Sprintf(`"%s"`, clause.path))
@@ -473,29 +1697,268 @@ func generateCode(writer io.Writer, info packageTypeInfo, options ...Option) err
 			importStmts = append(importStmts, fmt.Sprintf(`%s "%s"`, clause.aliasName, clause.path))
 		}
 	}
+	return importStmts
+}
+
+// codeGenerator keeps the state shared by the wrappers generated into one file
+type codeGenerator struct {
+	conf             generateConfig
+	info             packageTypeInfo
+	variables        templateVariables
+	importController *importer
+	withClassifier   bool
+}
+
+// templateMethods are the methods of a wrapper with the code attributes of the traced ones
+type templateMethods struct {
+	methods        []templateMethod
+	skippedMethods []string
+	code           *templateCode
+}
+
+// generateTracedMethod generates a method recording a span
+func (g *codeGenerator) generateTracedMethod(
+	interfaceDetail interfaceInfo,
+	method methodType,
+	local map[string]recognizedType,
+	options *templateOptions,
+	spanNameOf func(name string) string,
+) templateMethod {
+	conf := g.conf
+	global := g.variables.globalVariables
+	methodCtx := findMethodContext(method, conf.allMethods)
+	generated := generateCodeForMethod(global, local, method, methodCtx, g.importController)
+
+	kind := resolveSpanKind(conf, interfaceDetail, method)
+	if kind != "" {
+		generated.SpanKind = generateSpanKindString(kind, g.importController)
+	}
+	if options != nil {
+		generated.StartOptions = options.startOptionsField(kind, generated.SpanKind)
+	}
+	if conf.streaming {
+		generated.Stream = newTemplateStream(global, local, method, generated.SpanName, g.importController)
+	}
+	if conf.callbacks && callbacksSupported(methodCtx) {
+		setTemplateCallbacks(global, local, method, &generated, spanNameOf, g.importController)
+	}
+	return generated
+}
+
+// generateMethods generates the methods of the wrapper of an interface
+func (g *codeGenerator) generateMethods(
+	interfaceIndex int, interfaceDetail interfaceInfo, pkgPath string, options *templateOptions,
+) templateMethods {
+	conf := g.conf
+
+	var result templateMethods
+	if conf.codeAttributes {
+		result.code = &templateCode{
+			KeyValue: replacePackageName("attribute.KeyValue", attributePkgList(), g.importController),
+		}
+	}
+	namespace := pkgPath + "." + interfaceDetail.name
+	if interfaceDetail.structName != "" {
+		namespace = pkgPath + "." + interfaceDetail.structName
+	}
+
+	spanNameOf := func(name string) string {
+		if options != nil {
+			return fmt.Sprintf("w.spanName(%q, %q)", interfaceDetail.name, name)
+		}
+		return fmt.Sprintf("w.prefix + %q", name)
+	}
+
+	tracedCount := 0
+	for methodIndex, method := range interfaceDetail.methods {
+		methodCtx := findMethodContext(method, conf.allMethods)
+		if method.skipped {
+			result.skippedMethods = append(result.skippedMethods, method.name)
+		}
+		if methodCtx.strategy == contextStrategyNone {
+			if conf.noEmbed {
+				result.methods = append(result.methods, generateForwardMethod(method, g.importController))
+			}
+			continue
+		}
+		local := g.variables.interfaces[interfaceIndex].methods[methodIndex].variables
+		generated := g.generateTracedMethod(interfaceDetail, method, local, options, spanNameOf)
+		generated.Index = tracedCount
+		tracedCount++
+		result.methods = append(result.methods, generated)
+
+		if result.code != nil {
+			result.code.Methods = append(result.code.Methods,
+				generateCodeAttributesString(namespace, method, conf.codeLocation, g.importController))
+		}
+	}
+	return result
+}
+
+// generateInterface generates the wrapper of an interface, or the wrapping function of a func type
+func (g *codeGenerator) generateInterface(
+	interfaceIndex int, interfaceDetail interfaceInfo,
+) (templateInterface, error) {
+	conf := g.conf
+	importController := g.importController
+	pkgPath := g.info.interfacePkgPath(interfaceDetail)
+	if interfaceDetail.funcType {
+		local := g.variables.interfaces[interfaceIndex].methods[0].variables
+		return generateCodeForFunc(
+			conf, g.variables.globalVariables, local, pkgPath, interfaceDetail, importController,
+		)
+	}
+
+	var options *templateOptions
+	if conf.withOptions {
+		options = newTemplateOptions(pkgPath, importController)
+	}
+	generated := g.generateMethods(interfaceIndex, interfaceDetail, pkgPath, options)
+
+	embeddedInterfaceName := qualifiedTypeName(interfaceDetail.name, pkgPath, importController)
+	var structInfo *templateStruct
+	if interfaceDetail.structName != "" {
+		embeddedInterfaceName = interfaceDetail.name
+		structInfo = newTemplateStruct(pkgPath, interfaceDetail, importController)
+	}
+	field := interfaceDetail.name
+	if conf.noEmbed {
+		field = "wrapped"
+	}
+
+	var metrics *templateMetrics
+	if conf.withMetrics {
+		metrics = newTemplateMetrics(conf.namePrefix+interfaceDetail.name+"MetricsWrapper", importController)
+	}
+	var panics *templatePanics
+	if conf.recordPanics {
+		panics = newTemplatePanics(importController)
+	}
+	var fallback *templateFallback
+	if usesFallbackContext(interfaceDetail, conf.allMethods) {
+		fallback = &templateFallback{
+			ContextPkg: importController.chosenName("context"),
+		}
+	}
+	var classifier *templateClassifier
+	if g.withClassifier {
+		classifier = newTemplateClassifier(conf.errorClassifier, g.info.sentinels, importController)
+	}
+
+	return templateInterface{
+		Name:       embeddedInterfaceName,
+		UsedName:   interfaceDetail.name,
+		StructName: conf.namePrefix + interfaceDetail.name + "Wrapper",
+		NoEmbed:    conf.noEmbed,
+		Field:      field,
+		Unwrap:     !hasMethod(interfaceDetail, unwrapMethodName),
+		Methods:    generated.methods,
+
+		SkippedMethods: generated.skippedMethods,
+
+		ChosenOtelTracer: chosenOtelTracer(importController),
+
+		TypeParams: generateTypeParamsString(interfaceDetail.typeParams, importController),
+		TypeArgs:   generateTypeArgsString(interfaceDetail.typeParams, nil, importController),
+		InterfaceTypeArgs: generateTypeArgsString(
+			interfaceDetail.typeParams, interfaceDetail.typeArgs, importController,
+		),
+
+		Options:    options,
+		Metrics:    metrics,
+		Panics:     panics,
+		Classifier: classifier,
+		Code:       generated.code,
+		Fallback:   fallback,
+		Struct:     structInfo,
+	}, nil
+}
+
+func generateCode(writer io.Writer, info packageTypeInfo, options ...Option) error {
+	conf := computeGenerateConfig(options...)
+	tmpl, err := loadTemplate(conf)
+	if err != nil {
+		return err
+	}
+	info = markSkippedMethods(info, conf.skipMethods)
+
+	importController := newImporter()
+	withClassifier := containsWrappers(info) && (conf.errorClassifier || len(info.sentinels) > 0)
+	addPackageImports(importController, info, conf)
+	addFeatureImports(importController, info, conf, withClassifier)
+	addFallbackContextImport(importController, info, conf)
+
+	info.imports = chosenImports(importController)
+
+	variables := collectVariables(info)
+	info = assignVariableNames(info)
+	reportMethods(info, conf)
+
+	g := &codeGenerator{
+		conf:             conf,
+		info:             info,
+		variables:        variables,
+		importController: importController,
+		withClassifier:   withClassifier,
+	}
+	var interfaces []templateInterface
+	for interfaceIndex, interfaceDetail := range info.interfaces {
+		generated, err := g.generateInterface(interfaceIndex, interfaceDetail)
+		if err != nil {
+			return err
+		}
+		interfaces = append(interfaces, generated)
+	}
 
 	packageName := info.name
 	if conf.pkgName != "" {
 		packageName = conf.pkgName
 	}
 
-	return resultTemplate.Execute(writer, templatePackageInfo{
+	return tmpl.Execute(writer, templatePackageInfo{
 		PackageName: packageName,
-		Imports:     importStmts,
+		Imports:     importStatements(importController),
 		Interfaces:  interfaces,
 	})
 }
 
-// LoadAndGenerate ...
-func LoadAndGenerate(w io.Writer, pattern string, interfaceNames []string, options ...Option) error {
-	info, err := loadPackageTypeData(pattern, interfaceNames...)
+// generatedFileHeader is written before the generated code
+const generatedFileHeader = `// Code generated by otelwrap; DO NOT EDIT.
+// github.com/QuangTung97/otelwrap
+
+`
+
+// Render returns the generated code formatted with gofmt, after the header of generated files
+func Render(generateFunc func(w io.Writer) error) ([]byte, error) {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString(generatedFileHeader)
+	err := generateFunc(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, WrapError(err, StageFormat, "")
+	}
+	return data, nil
+}
+
+// LoadAndGenerateGroups generates wrappers of interfaces of multiple packages into one file,
+// in the package of the first group unless WithInAnotherPackage is specified
+func LoadAndGenerateGroups(w io.Writer, groups []InterfaceGroup, options ...Option) error {
+	info, err := loadPackageTypeDataForGroups(groups, computeGenerateConfig(options...))
 	if err != nil {
 		return err
 	}
-	err = generateCode(w, info, options...)
+	return WrapError(generateCode(w, info, options...), StageGenerate, "")
+}
+
+// LoadAndGenerate ...
+func LoadAndGenerate(w io.Writer, pattern string, interfaceNames []string, options ...Option) error {
+	info, err := loadPackageTypeDataWithConfig(pattern, interfaceNames, computeGenerateConfig(options...))
 	if err != nil {
-		fmt.Println("generateCode", err)
 		return err
 	}
-	return nil
+	return WrapError(generateCode(w, info, options...), StageGenerate, "")
 }
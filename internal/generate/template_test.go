@@ -2,7 +2,10 @@ package generate
 
 import (
 	"bytes"
+	"errors"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/tools/go/packages"
+	"path/filepath"
 	"testing"
 )
 
@@ -330,6 +333,8 @@ type HandlerWrapper struct {
 	prefix string
 }
 
+var _ Handler = (*HandlerWrapper)(nil)
+
 // NewHandlerWrapper creates a wrapper
 func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *HandlerWrapper {
 	return &HandlerWrapper{
@@ -339,6 +344,19 @@ func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *Han
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
 // Hello ...
 func (w *HandlerWrapper) Hello(ctx context.Context, n int, createdAt time.Time) (err error) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "Hello")
@@ -520,6 +538,8 @@ type HandlerWrapper struct {
 	prefix string
 }
 
+var _ Handler = (*HandlerWrapper)(nil)
+
 // NewHandlerWrapper creates a wrapper
 func NewHandlerWrapper(wrapped Handler, tracer oteltrace.Tracer, prefix string) *HandlerWrapper {
 	return &HandlerWrapper{
@@ -529,6 +549,19 @@ func NewHandlerWrapper(wrapped Handler, tracer oteltrace.Tracer, prefix string)
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
 // Hello ...
 func (w *HandlerWrapper) Hello(ctx context.Context, n int, createdAt time.Time, value *codes.Hello, t *trace.Hello) (err error) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "Hello")
@@ -631,6 +664,8 @@ type HandlerWrapper struct {
 	prefix string
 }
 
+var _ Handler = (*HandlerWrapper)(nil)
+
 // NewHandlerWrapper creates a wrapper
 func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *HandlerWrapper {
 	return &HandlerWrapper{
@@ -640,6 +675,19 @@ func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *Han
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
 // WithoutName ...
 func (w *HandlerWrapper) WithoutName(ctx context.Context, a int) (a1 string, err error) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "WithoutName")
@@ -711,6 +759,8 @@ type HandlerWrapper struct {
 	prefix string
 }
 
+var _ Handler = (*HandlerWrapper)(nil)
+
 // NewHandlerWrapper creates a wrapper
 func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *HandlerWrapper {
 	return &HandlerWrapper{
@@ -720,6 +770,19 @@ func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *Han
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
 // WithoutName ...
 func (w *HandlerWrapper) WithoutName(ctx context.Context, a int) (err error) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "WithoutName")
@@ -791,6 +854,8 @@ type HandlerWrapper struct {
 	prefix string
 }
 
+var _ Handler = (*HandlerWrapper)(nil)
+
 // NewHandlerWrapper creates a wrapper
 func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *HandlerWrapper {
 	return &HandlerWrapper{
@@ -800,6 +865,19 @@ func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *Han
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
 // WithoutName ...
 func (w *HandlerWrapper) WithoutName(ctx context.Context, u *User) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "WithoutName")
@@ -867,6 +945,8 @@ type HandlerWrapper struct {
 	prefix string
 }
 
+var _ example.Handler = (*HandlerWrapper)(nil)
+
 // NewHandlerWrapper creates a wrapper
 func NewHandlerWrapper(wrapped example.Handler, tracer trace.Tracer, prefix string) *HandlerWrapper {
 	return &HandlerWrapper{
@@ -876,6 +956,19 @@ func NewHandlerWrapper(wrapped example.Handler, tracer trace.Tracer, prefix stri
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() example.Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
 // WithoutName ...
 func (w *HandlerWrapper) WithoutName(ctx context.Context, u *example.User) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "WithoutName")
@@ -965,6 +1058,8 @@ type HandlerWrapper struct {
 	prefix string
 }
 
+var _ example.Handler = (*HandlerWrapper)(nil)
+
 // NewHandlerWrapper creates a wrapper
 func NewHandlerWrapper(wrapped example.Handler, tracer trace.Tracer, prefix string) *HandlerWrapper {
 	return &HandlerWrapper{
@@ -974,6 +1069,19 @@ func NewHandlerWrapper(wrapped example.Handler, tracer trace.Tracer, prefix stri
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() example.Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
 // HelloWorld ...
 func (w *HandlerWrapper) HelloWorld(ctx context.Context, u *example.User) (err error) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "HelloWorld")
@@ -1064,6 +1172,8 @@ type HandlerWrapper struct {
 	prefix string
 }
 
+var _ Handler = (*HandlerWrapper)(nil)
+
 // NewHandlerWrapper creates a wrapper
 func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *HandlerWrapper {
 	return &HandlerWrapper{
@@ -1073,6 +1183,19 @@ func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *Han
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
 // HelloWorld ...
 func (w *HandlerWrapper) HelloWorld(ctx context.Context, u *User) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "HelloWorld")
@@ -1088,6 +1211,8 @@ type IRepoWrapper struct {
 	prefix string
 }
 
+var _ IRepo = (*IRepoWrapper)(nil)
+
 // NewIRepoWrapper creates a wrapper
 func NewIRepoWrapper(wrapped IRepo, tracer trace.Tracer, prefix string) *IRepoWrapper {
 	return &IRepoWrapper{
@@ -1097,6 +1222,19 @@ func NewIRepoWrapper(wrapped IRepo, tracer trace.Tracer, prefix string) *IRepoWr
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *IRepoWrapper) Unwrap() IRepo {
+	return w.IRepo
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *IRepoWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.IRepo
+}
+
 // GetUser ...
 func (w *IRepoWrapper) GetUser(ctx context.Context, id int) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "GetUser")
@@ -1164,6 +1302,8 @@ type HandlerWrapper struct {
 	prefix string
 }
 
+var _ Handler = (*HandlerWrapper)(nil)
+
 // NewHandlerWrapper creates a wrapper
 func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *HandlerWrapper {
 	return &HandlerWrapper{
@@ -1173,6 +1313,19 @@ func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *Han
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
 // ManyParams ...
 func (w *HandlerWrapper) ManyParams(ctx context.Context, names ...string) (err error) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "ManyParams")
@@ -1245,6 +1398,8 @@ type HandlerWrapper struct {
 	prefix string
 }
 
+var _ Handler = (*HandlerWrapper)(nil)
+
 // NewHandlerWrapper creates a wrapper
 func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *HandlerWrapper {
 	return &HandlerWrapper{
@@ -1254,6 +1409,19 @@ func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *Han
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
 // GetName ...
 func (w *HandlerWrapper) GetName(ctx context.Context, a string) (err error) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "GetName")
@@ -1320,6 +1488,8 @@ type HandlerWrapper struct {
 	prefix string
 }
 
+var _ Handler = (*HandlerWrapper)(nil)
+
 // NewHandlerWrapper creates a wrapper
 func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *HandlerWrapper {
 	return &HandlerWrapper{
@@ -1329,6 +1499,19 @@ func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *Han
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
 // GetName ...
 func (w *HandlerWrapper) GetName(ctx context.Context) (a int64) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "GetName")
@@ -1340,3 +1523,2022 @@ func (w *HandlerWrapper) GetName(ctx context.Context) (a int64) {
 }
 `, buf.String())
 }
+
+func TestGenerateCode_With_Attributes(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		imports: []importInfo{
+			{
+				path: "context",
+				name: "context",
+			},
+		},
+		interfaces: []interfaceInfo{
+			{
+				name: "Handler",
+				methods: []methodType{
+					{
+						name: "GetUser",
+						params: []tupleType{
+							{
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+							{
+								name:    "id",
+								typeStr: "UserID",
+							},
+							{
+								name:    "w",
+								typeStr: "string",
+							},
+						},
+						results: []tupleType{
+							{
+								typeStr:    "error",
+								recognized: recognizedTypeError,
+							},
+						},
+						attributes: []spanAttribute{
+							{
+								key:         "user.id",
+								index:       1,
+								constructor: "Int64",
+								conversion:  "int64",
+							},
+							{
+								key:         "user.name",
+								index:       2,
+								constructor: "String",
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package example
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// HandlerWrapper wraps OpenTelemetry's span
+type HandlerWrapper struct {
+	Handler
+	tracer trace.Tracer
+	prefix string
+}
+
+var _ Handler = (*HandlerWrapper)(nil)
+
+// NewHandlerWrapper creates a wrapper
+func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *HandlerWrapper {
+	return &HandlerWrapper{
+		Handler: wrapped,
+		tracer: tracer,
+		prefix: prefix,
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
+// GetUser ...
+func (w *HandlerWrapper) GetUser(ctx context.Context, id UserID, b string) (err error) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "GetUser")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int64("user.id", int64(id)),
+		attribute.String("user.name", b),
+	)
+
+	err = w.Handler.GetUser(ctx, id, b)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+`, buf.String())
+}
+
+func TestGenerateCode_With_Metrics(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		imports: []importInfo{
+			{
+				path: "context",
+				name: "context",
+			},
+		},
+		interfaces: []interfaceInfo{
+			{
+				name: "Handler",
+				methods: []methodType{
+					{
+						name: "Hello",
+						params: []tupleType{
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+							{
+								name:    "start",
+								typeStr: "int",
+							},
+						},
+						results: []tupleType{
+							{
+								typeStr:    "error",
+								recognized: recognizedTypeError,
+							},
+						},
+					},
+					{
+						name: "Run",
+						params: []tupleType{
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+						},
+					},
+				},
+			},
+		},
+	}, WithMetrics())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package example
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/codes"
+	"time"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// HandlerWrapper wraps OpenTelemetry's span
+type HandlerWrapper struct {
+	Handler
+	tracer trace.Tracer
+	prefix string
+}
+
+var _ Handler = (*HandlerWrapper)(nil)
+
+// NewHandlerWrapper creates a wrapper
+func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *HandlerWrapper {
+	return &HandlerWrapper{
+		Handler: wrapped,
+		tracer: tracer,
+		prefix: prefix,
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
+// Hello ...
+func (w *HandlerWrapper) Hello(ctx context.Context, start int) (err error) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "Hello")
+	defer span.End()
+
+	err = w.Handler.Hello(ctx, start)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// Run ...
+func (w *HandlerWrapper) Run(ctx context.Context) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "Run")
+	defer span.End()
+
+	w.Handler.Run(ctx)
+}
+
+// HandlerMetricsWrapper wraps OpenTelemetry's metrics
+type HandlerMetricsWrapper struct {
+	Handler
+	requests metric.Int64Counter
+	errors metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+var _ Handler = (*HandlerMetricsWrapper)(nil)
+
+// NewHandlerMetricsWrapper creates a metrics wrapper
+func NewHandlerMetricsWrapper(wrapped Handler, meter metric.Meter, prefix string) (*HandlerMetricsWrapper, error) {
+	requests, err := meter.Int64Counter(prefix + "requests")
+	if err != nil {
+		return nil, err
+	}
+	errorCounter, err := meter.Int64Counter(prefix + "errors")
+	if err != nil {
+		return nil, err
+	}
+	duration, err := meter.Float64Histogram(prefix + "duration", metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	return &HandlerMetricsWrapper{
+		Handler: wrapped,
+		requests: requests,
+		errors: errorCounter,
+		duration: duration,
+	}, nil
+}
+
+// Unwrap returns the wrapped implementation
+func (w *HandlerMetricsWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerMetricsWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
+func (w *HandlerMetricsWrapper) record(ctx context.Context, method string, start time.Time, failed bool) {
+	attrs := metric.WithAttributes(
+		attribute.String("method", method),
+		attribute.Bool("error", failed),
+	)
+	w.requests.Add(ctx, 1, attrs)
+	if failed {
+		w.errors.Add(ctx, 1, attrs)
+	}
+	w.duration.Record(ctx, time.Since(start).Seconds(), attrs)
+}
+
+// Hello ...
+func (w *HandlerMetricsWrapper) Hello(ctx context.Context, start int) (err error) {
+	start1 := time.Now()
+	err = w.Handler.Hello(ctx, start)
+	w.record(ctx, "Hello", start1, err != nil)
+	return err
+}
+
+// Run ...
+func (w *HandlerMetricsWrapper) Run(ctx context.Context) {
+	start := time.Now()
+	w.Handler.Run(ctx)
+	w.record(ctx, "Run", start, false)
+}
+`, buf.String())
+}
+
+func TestGenerateCode_With_Record_Panics(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		imports: []importInfo{
+			{
+				path: "context",
+				name: "context",
+			},
+		},
+		interfaces: []interfaceInfo{
+			{
+				name: "Handler",
+				methods: []methodType{
+					{
+						name: "Run",
+						params: []tupleType{
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+							{
+								name:    "fmt",
+								typeStr: "string",
+							},
+						},
+					},
+				},
+			},
+		},
+	}, WithRecordPanics())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package example
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/codes"
+	"fmt"
+)
+
+// HandlerWrapper wraps OpenTelemetry's span
+type HandlerWrapper struct {
+	Handler
+	tracer trace.Tracer
+	prefix string
+}
+
+var _ Handler = (*HandlerWrapper)(nil)
+
+// NewHandlerWrapper creates a wrapper
+func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *HandlerWrapper {
+	return &HandlerWrapper{
+		Handler: wrapped,
+		tracer: tracer,
+		prefix: prefix,
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
+// Run ...
+func (w *HandlerWrapper) Run(ctx context.Context, a string) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "Run")
+	defer func() {
+		if r := recover(); r != nil {
+			span.RecordError(fmt.Errorf("panic: %v", r), trace.WithStackTrace(true))
+			span.SetStatus(codes.Error, fmt.Sprint(r))
+			span.End()
+			panic(r)
+		}
+		span.End()
+	}()
+
+	w.Handler.Run(ctx, a)
+}
+`, buf.String())
+}
+
+//revive:disable:line-length-limit
+func TestGenerateCode_With_Error_Classifier(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		imports: []importInfo{
+			{
+				path: "context",
+				name: "context",
+			},
+		},
+		interfaces: []interfaceInfo{
+			{
+				name: "Handler",
+				methods: []methodType{
+					{
+						name: "Hello",
+						params: []tupleType{
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+						},
+						results: []tupleType{
+							{
+								typeStr:    "error",
+								recognized: recognizedTypeError,
+							},
+						},
+					},
+				},
+			},
+		},
+		sentinels: []sentinelError{
+			{
+				pkg: importInfo{
+					name: "sql",
+					path: "database/sql",
+				},
+				name: "ErrNoRows",
+			},
+			{
+				pkg: importInfo{
+					name: "example",
+					path: "hello/example",
+				},
+				name: "ErrNotFound",
+			},
+		},
+	}, WithErrorClassifier())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package example
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/codes"
+	"github.com/QuangTung97/otelwrap/support"
+	"database/sql"
+)
+
+// HandlerWrapper wraps OpenTelemetry's span
+type HandlerWrapper struct {
+	Handler
+	tracer trace.Tracer
+	prefix string
+	classifier support.ErrorClassifier
+}
+
+var _ Handler = (*HandlerWrapper)(nil)
+
+// NewHandlerWrapper creates a wrapper
+func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string, classifier support.ErrorClassifier) *HandlerWrapper {
+	if classifier == nil {
+		classifier = support.IgnoreErrors(support.DefaultErrorClassifier, sql.ErrNoRows, ErrNotFound)
+	}
+	return &HandlerWrapper{
+		Handler: wrapped,
+		tracer: tracer,
+		prefix: prefix,
+		classifier: classifier,
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
+// Hello ...
+func (w *HandlerWrapper) Hello(ctx context.Context) (err error) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "Hello")
+	defer span.End()
+
+	err = w.Handler.Hello(ctx)
+	if err != nil {
+		switch w.classifier(err) {
+		case support.ErrorFailed:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		case support.ErrorRecorded:
+			span.RecordError(err)
+		}
+	}
+	return err
+}
+`, buf.String())
+}
+
+//revive:enable:line-length-limit
+
+func TestGenerateCode_With_Options_Constructor(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		imports: []importInfo{
+			{
+				path: "context",
+				name: "context",
+			},
+		},
+		interfaces: []interfaceInfo{
+			{
+				name: "Handler",
+				methods: []methodType{
+					{
+						name: "Hello",
+						params: []tupleType{
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+						},
+						results: []tupleType{
+							{
+								typeStr:    "error",
+								recognized: recognizedTypeError,
+							},
+						},
+					},
+				},
+			},
+		},
+	}, WithOptionsConstructor(), WithErrorClassifier())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package example
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/codes"
+	"github.com/QuangTung97/otelwrap/support"
+)
+
+// HandlerWrapper wraps OpenTelemetry's span
+type HandlerWrapper struct {
+	Handler
+	tracer trace.Tracer
+	spanName support.SpanNameFormatter
+	startOptions []trace.SpanStartOption
+	classifier support.ErrorClassifier
+}
+
+var _ Handler = (*HandlerWrapper)(nil)
+
+// NewHandlerWrapper creates a wrapper
+func NewHandlerWrapper(wrapped Handler, opts ...support.Option) *HandlerWrapper {
+	conf := support.NewConfig("hello/example", opts...)
+	classifier := conf.ErrorClassifier
+	if classifier == nil {
+		classifier = support.DefaultErrorClassifier
+	}
+	return &HandlerWrapper{
+		Handler: wrapped,
+		tracer: conf.Tracer,
+		spanName: conf.SpanNameFormatter,
+		startOptions: conf.SpanStartOptions,
+		classifier: classifier,
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
+// Hello ...
+func (w *HandlerWrapper) Hello(ctx context.Context) (err error) {
+	ctx, span := w.tracer.Start(ctx, w.spanName("Handler", "Hello"), w.startOptions...)
+	defer span.End()
+
+	err = w.Handler.Hello(ctx)
+	if err != nil {
+		switch w.classifier(err) {
+		case support.ErrorFailed:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		case support.ErrorRecorded:
+			span.RecordError(err)
+		}
+	}
+	return err
+}
+`, buf.String())
+}
+
+func TestGenerateCode_With_Code_Attributes(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		imports: []importInfo{
+			{
+				path: "context",
+				name: "context",
+			},
+		},
+		interfaces: []interfaceInfo{
+			{
+				name: "Handler",
+				methods: []methodType{
+					{
+						name: "Hello",
+						params: []tupleType{
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+						},
+						location: codeLocation{
+							filePath: "hello/example/handler.go",
+							line:     12,
+						},
+					},
+					{
+						name: "Skipped",
+					},
+					{
+						name: "Run",
+						params: []tupleType{
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+						},
+						location: codeLocation{
+							filePath: "hello/example/handler.go",
+							line:     14,
+						},
+					},
+				},
+			},
+		},
+	}, WithCodeLocation())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package example
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// HandlerWrapper wraps OpenTelemetry's span
+type HandlerWrapper struct {
+	Handler
+	tracer trace.Tracer
+	prefix string
+	codeAttributes [][]attribute.KeyValue
+}
+
+var _ Handler = (*HandlerWrapper)(nil)
+
+// NewHandlerWrapper creates a wrapper
+func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *HandlerWrapper {
+	return &HandlerWrapper{
+		Handler: wrapped,
+		tracer: tracer,
+		prefix: prefix,
+		codeAttributes: [][]attribute.KeyValue{
+			{
+				attribute.String("code.function", "Hello"),
+				attribute.String("code.namespace", "hello/example.Handler"),
+				attribute.String("code.filepath", "hello/example/handler.go"),
+				attribute.Int("code.lineno", 12),
+			},
+			{
+				attribute.String("code.function", "Run"),
+				attribute.String("code.namespace", "hello/example.Handler"),
+				attribute.String("code.filepath", "hello/example/handler.go"),
+				attribute.Int("code.lineno", 14),
+			},
+		},
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
+// Hello ...
+func (w *HandlerWrapper) Hello(ctx context.Context) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "Hello")
+	defer span.End()
+	span.SetAttributes(w.codeAttributes[0]...)
+
+	w.Handler.Hello(ctx)
+}
+
+// Run ...
+func (w *HandlerWrapper) Run(ctx context.Context) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "Run")
+	defer span.End()
+	span.SetAttributes(w.codeAttributes[1]...)
+
+	w.Handler.Run(ctx)
+}
+`, buf.String())
+}
+
+func newSpanKindPackageTypeInfo() packageTypeInfo {
+	method := func(name string, spanKind string) methodType {
+		return methodType{
+			name: name,
+			params: []tupleType{
+				{
+					name:       "ctx",
+					typeStr:    "context.Context",
+					recognized: recognizedTypeContext,
+					pkgList:    pkgListContext(),
+				},
+			},
+			spanKind: spanKind,
+		}
+	}
+
+	return packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		imports: []importInfo{
+			{
+				path: "context",
+				name: "context",
+			},
+		},
+		interfaces: []interfaceInfo{
+			{
+				name: "Handler",
+				methods: []methodType{
+					method("Get", ""),
+					method("Publish", "producer"),
+				},
+				spanKind: "client",
+			},
+			{
+				name: "Server",
+				methods: []methodType{
+					method("Serve", ""),
+				},
+			},
+		},
+	}
+}
+
+func TestGenerateCode_With_Span_Kind(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, newSpanKindPackageTypeInfo(), WithSpanKind("server"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package example
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HandlerWrapper wraps OpenTelemetry's span
+type HandlerWrapper struct {
+	Handler
+	tracer trace.Tracer
+	prefix string
+}
+
+var _ Handler = (*HandlerWrapper)(nil)
+
+// NewHandlerWrapper creates a wrapper
+func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *HandlerWrapper {
+	return &HandlerWrapper{
+		Handler: wrapped,
+		tracer: tracer,
+		prefix: prefix,
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
+// Get ...
+func (w *HandlerWrapper) Get(ctx context.Context) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "Get", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	w.Handler.Get(ctx)
+}
+
+// Publish ...
+func (w *HandlerWrapper) Publish(ctx context.Context) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "Publish", trace.WithSpanKind(trace.SpanKindProducer))
+	defer span.End()
+
+	w.Handler.Publish(ctx)
+}
+
+// ServerWrapper wraps OpenTelemetry's span
+type ServerWrapper struct {
+	Server
+	tracer trace.Tracer
+	prefix string
+}
+
+var _ Server = (*ServerWrapper)(nil)
+
+// NewServerWrapper creates a wrapper
+func NewServerWrapper(wrapped Server, tracer trace.Tracer, prefix string) *ServerWrapper {
+	return &ServerWrapper{
+		Server: wrapped,
+		tracer: tracer,
+		prefix: prefix,
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *ServerWrapper) Unwrap() Server {
+	return w.Server
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *ServerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Server
+}
+
+// Serve ...
+func (w *ServerWrapper) Serve(ctx context.Context) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "Serve", trace.WithSpanKind(trace.SpanKindServer))
+	defer span.End()
+
+	w.Server.Serve(ctx)
+}
+`, buf.String())
+}
+
+//revive:disable:line-length-limit
+func TestGenerateCode_With_Span_Kind_And_Options_Constructor(t *testing.T) {
+	info := newSpanKindPackageTypeInfo()
+	info.interfaces = info.interfaces[:1]
+
+	var buf bytes.Buffer
+	err := generateCode(&buf, info, WithOptionsConstructor())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package example
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+	"github.com/QuangTung97/otelwrap/support"
+)
+
+// HandlerWrapper wraps OpenTelemetry's span
+type HandlerWrapper struct {
+	Handler
+	tracer trace.Tracer
+	spanName support.SpanNameFormatter
+	startOptions []trace.SpanStartOption
+	clientStartOptions []trace.SpanStartOption
+	producerStartOptions []trace.SpanStartOption
+}
+
+var _ Handler = (*HandlerWrapper)(nil)
+
+// NewHandlerWrapper creates a wrapper
+func NewHandlerWrapper(wrapped Handler, opts ...support.Option) *HandlerWrapper {
+	conf := support.NewConfig("hello/example", opts...)
+	return &HandlerWrapper{
+		Handler: wrapped,
+		tracer: conf.Tracer,
+		spanName: conf.SpanNameFormatter,
+		startOptions: conf.SpanStartOptions,
+		clientStartOptions: append([]trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindClient)}, conf.SpanStartOptions...),
+		producerStartOptions: append([]trace.SpanStartOption{trace.WithSpanKind(trace.SpanKindProducer)}, conf.SpanStartOptions...),
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
+// Get ...
+func (w *HandlerWrapper) Get(ctx context.Context) {
+	ctx, span := w.tracer.Start(ctx, w.spanName("Handler", "Get"), w.clientStartOptions...)
+	defer span.End()
+
+	w.Handler.Get(ctx)
+}
+
+// Publish ...
+func (w *HandlerWrapper) Publish(ctx context.Context) {
+	ctx, span := w.tracer.Start(ctx, w.spanName("Handler", "Publish"), w.producerStartOptions...)
+	defer span.End()
+
+	w.Handler.Publish(ctx)
+}
+`, buf.String())
+}
+
+//revive:enable:line-length-limit
+
+func TestGenerateCode_With_Invalid_Span_Kind(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, newSpanKindPackageTypeInfo(), WithSpanKind("remote"))
+	assert.Equal(t, &Error{
+		Stage: StageArgs,
+		Err:   errors.New("invalid span kind 'remote', expected internal, server, client, producer or consumer"),
+	}, err)
+}
+
+//revive:disable:line-length-limit
+func TestGenerateCode_With_All_Methods(t *testing.T) {
+	httpPkgList := []tupleTypePkg{
+		{
+			path:  "net/http",
+			begin: 1,
+			end:   5,
+		},
+	}
+
+	var report bytes.Buffer
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		imports: []importInfo{
+			{
+				path: "context",
+				name: "context",
+			},
+			{
+				path: "net/http",
+				name: "http",
+			},
+		},
+		interfaces: []interfaceInfo{
+			{
+				name: "Handler",
+				methods: []methodType{
+					{
+						name: "Get",
+						params: []tupleType{
+							{
+								name:    "id",
+								typeStr: "int64",
+							},
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+						},
+					},
+					{
+						name: "Serve",
+						params: []tupleType{
+							{
+								typeStr:    "*http.Request",
+								recognized: recognizedTypeHTTPRequest,
+								pkgList:    httpPkgList,
+							},
+						},
+					},
+					{
+						name: "Check",
+						results: []tupleType{
+							{
+								typeStr:    "error",
+								recognized: recognizedTypeError,
+							},
+						},
+					},
+				},
+			},
+		},
+	}, WithAllMethods(), WithContextReport(&report))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package example
+
+import (
+	"context"
+	"net/http"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// HandlerWrapper wraps OpenTelemetry's span
+type HandlerWrapper struct {
+	Handler
+	tracer trace.Tracer
+	prefix string
+	fallbackCtx context.Context
+}
+
+var _ Handler = (*HandlerWrapper)(nil)
+
+// NewHandlerWrapper creates a wrapper
+func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string, fallbackCtx context.Context) *HandlerWrapper {
+	if fallbackCtx == nil {
+		fallbackCtx = context.Background()
+	}
+	return &HandlerWrapper{
+		Handler: wrapped,
+		tracer: tracer,
+		prefix: prefix,
+		fallbackCtx: fallbackCtx,
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.Handler
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Handler
+}
+
+// Get ...
+func (w *HandlerWrapper) Get(id int64, ctx context.Context) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "Get")
+	defer span.End()
+
+	w.Handler.Get(id, ctx)
+}
+
+// Serve ...
+func (w *HandlerWrapper) Serve(a *http.Request) {
+	ctx, span := w.tracer.Start(a.Context(), w.prefix + "Serve")
+	defer span.End()
+	a = a.WithContext(ctx)
+
+	w.Handler.Serve(a)
+}
+
+// Check ...
+func (w *HandlerWrapper) Check() (err error) {
+	_, span := w.tracer.Start(w.fallbackCtx, w.prefix + "Check")
+	defer span.End()
+
+	err = w.Handler.Check()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+`, buf.String())
+
+	assert.Equal(t, `Handler.Get: context param 'ctx'
+Handler.Serve: context of request param 'a'
+Handler.Check: fallback context of the wrapper
+`, report.String())
+}
+
+//revive:enable:line-length-limit
+
+func TestGenerateCode_With_No_Embed(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		imports: []importInfo{
+			{
+				path: "context",
+				name: "context",
+			},
+		},
+		interfaces: []interfaceInfo{
+			{
+				name: "Handler",
+				methods: []methodType{
+					{
+						name: "Get",
+						params: []tupleType{
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+							{
+								name:    "id",
+								typeStr: "int64",
+							},
+						},
+						results: []tupleType{
+							{
+								typeStr: "string",
+							},
+						},
+					},
+					{
+						name: "Check",
+						params: []tupleType{
+							{
+								typeStr: "int",
+							},
+						},
+						results: []tupleType{
+							{
+								typeStr:    "error",
+								recognized: recognizedTypeError,
+							},
+						},
+					},
+					{
+						name: "Close",
+					},
+				},
+			},
+		},
+	}, WithNoEmbed())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package example
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HandlerWrapper wraps OpenTelemetry's span
+type HandlerWrapper struct {
+	wrapped Handler
+	tracer trace.Tracer
+	prefix string
+}
+
+var _ Handler = (*HandlerWrapper)(nil)
+
+// NewHandlerWrapper creates a wrapper
+func NewHandlerWrapper(wrapped Handler, tracer trace.Tracer, prefix string) *HandlerWrapper {
+	return &HandlerWrapper{
+		wrapped: wrapped,
+		tracer: tracer,
+		prefix: prefix,
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *HandlerWrapper) Unwrap() Handler {
+	return w.wrapped
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.wrapped
+}
+
+// Get ...
+func (w *HandlerWrapper) Get(ctx context.Context, id int64) (a string) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "Get")
+	defer span.End()
+
+	a = w.wrapped.Get(ctx, id)
+	
+	return a
+}
+
+// Check ...
+func (w *HandlerWrapper) Check(a int) (err error) {
+	return w.wrapped.Check(a)
+}
+
+// Close ...
+func (w *HandlerWrapper) Close() {
+	w.wrapped.Close()
+}
+`, buf.String())
+}
+
+func TestGenerateCode_With_Unwrap_Method_In_Interface(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		interfaces: []interfaceInfo{
+			{
+				name: "Wrapper",
+				methods: []methodType{
+					{
+						name: "Unwrap",
+						results: []tupleType{
+							{
+								typeStr: "any",
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package example
+
+import (
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WrapperWrapper wraps OpenTelemetry's span
+type WrapperWrapper struct {
+	Wrapper
+	tracer trace.Tracer
+	prefix string
+}
+
+var _ Wrapper = (*WrapperWrapper)(nil)
+
+// NewWrapperWrapper creates a wrapper
+func NewWrapperWrapper(wrapped Wrapper, tracer trace.Tracer, prefix string) *WrapperWrapper {
+	return &WrapperWrapper{
+		Wrapper: wrapped,
+		tracer: tracer,
+		prefix: prefix,
+	}
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *WrapperWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Wrapper
+}
+`, buf.String())
+}
+
+func TestGenerateCode_For_Struct(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		imports: []importInfo{
+			{
+				path: "context",
+				name: "context",
+			},
+		},
+		interfaces: []interfaceInfo{
+			{
+				name:       "ServiceAPI",
+				structName: "Service",
+				methods: []methodType{
+					{
+						name: "Get",
+						params: []tupleType{
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+						},
+						results: []tupleType{
+							{
+								typeStr: "*User",
+								pkgList: []tupleTypePkg{
+									{
+										path:  "hello/example",
+										begin: 1,
+										end:   1,
+									},
+								},
+							},
+						},
+					},
+					{
+						name: "Close",
+					},
+				},
+			},
+		},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package example
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceAPI is the interface of the exported methods of *Service
+type ServiceAPI interface {
+	Get(ctx context.Context) (a *User)
+	Close()
+}
+
+var _ ServiceAPI = (*Service)(nil)
+
+// ServiceAPIWrapper wraps OpenTelemetry's span
+type ServiceAPIWrapper struct {
+	ServiceAPI
+	tracer trace.Tracer
+	prefix string
+}
+
+var _ ServiceAPI = (*ServiceAPIWrapper)(nil)
+
+// NewServiceAPIWrapper creates a wrapper
+func NewServiceAPIWrapper(wrapped ServiceAPI, tracer trace.Tracer, prefix string) *ServiceAPIWrapper {
+	return &ServiceAPIWrapper{
+		ServiceAPI: wrapped,
+		tracer: tracer,
+		prefix: prefix,
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *ServiceAPIWrapper) Unwrap() ServiceAPI {
+	return w.ServiceAPI
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *ServiceAPIWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.ServiceAPI
+}
+
+// Get ...
+func (w *ServiceAPIWrapper) Get(ctx context.Context) (a *User) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "Get")
+	defer span.End()
+
+	a = w.ServiceAPI.Get(ctx)
+	
+	return a
+}
+`, buf.String())
+}
+
+func TestGenerateCode_For_Func_Type(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		imports: []importInfo{
+			{
+				path: "context",
+				name: "context",
+			},
+		},
+		interfaces: []interfaceInfo{
+			{
+				name:     "HandlerFunc",
+				funcType: true,
+				methods: []methodType{
+					{
+						name: "HandlerFunc",
+						params: []tupleType{
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+							{
+								name:    "name",
+								typeStr: "string",
+							},
+						},
+						results: []tupleType{
+							{
+								typeStr:    "error",
+								recognized: recognizedTypeError,
+							},
+						},
+					},
+				},
+			},
+		},
+	}, WithOptionsConstructor(), WithSpanKind("server"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package example
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// WrapHandlerFunc wraps HandlerFunc with OpenTelemetry's span
+func WrapHandlerFunc(f HandlerFunc, tracer trace.Tracer, name1 string) HandlerFunc {
+	return func(ctx context.Context, name string) (err error) {
+		ctx, span := tracer.Start(ctx, name1, trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		err = f(ctx, name)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return err
+	}
+}
+`, buf.String())
+}
+
+func TestGenerateCode_For_Func_Type_Without_Context(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		interfaces: []interfaceInfo{
+			{
+				name:     "CheckFunc",
+				funcType: true,
+				methods: []methodType{
+					{
+						name: "CheckFunc",
+					},
+				},
+			},
+		},
+	})
+	assert.Equal(t, &Error{
+		Stage:     StageGenerate,
+		Interface: "CheckFunc",
+		Err:       errors.New("func type 'CheckFunc' has no context param"),
+	}, err)
+}
+
+func TestGenerateCode_With_Result_Attributes(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		imports: []importInfo{
+			{
+				path: "context",
+				name: "context",
+			},
+		},
+		interfaces: []interfaceInfo{
+			{
+				name: "Repo",
+				methods: []methodType{
+					{
+						name: "List",
+						params: []tupleType{
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+						},
+						results: []tupleType{
+							{
+								name:    "ids",
+								typeStr: "[]int64",
+							},
+							{
+								name:    "n",
+								typeStr: "int32",
+							},
+						},
+						resultAttributes: []spanAttribute{
+							{
+								key:         "result.count",
+								index:       0,
+								constructor: "Int",
+								length:      true,
+							},
+							{
+								key:         "result.total",
+								index:       1,
+								constructor: "Int64",
+								conversion:  "int64",
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package example
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RepoWrapper wraps OpenTelemetry's span
+type RepoWrapper struct {
+	Repo
+	tracer trace.Tracer
+	prefix string
+}
+
+var _ Repo = (*RepoWrapper)(nil)
+
+// NewRepoWrapper creates a wrapper
+func NewRepoWrapper(wrapped Repo, tracer trace.Tracer, prefix string) *RepoWrapper {
+	return &RepoWrapper{
+		Repo: wrapped,
+		tracer: tracer,
+		prefix: prefix,
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *RepoWrapper) Unwrap() Repo {
+	return w.Repo
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *RepoWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Repo
+}
+
+// List ...
+func (w *RepoWrapper) List(ctx context.Context) (ids []int64, n int32) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "List")
+	defer span.End()
+
+	ids, n = w.Repo.List(ctx)
+	
+	span.SetAttributes(
+		attribute.Int("result.count", len(ids)),
+		attribute.Int64("result.total", int64(n)),
+	)
+	return ids, n
+}
+`, buf.String())
+}
+
+func TestGenerateCode_With_Streaming(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		imports: []importInfo{
+			{
+				path: "context",
+				name: "context",
+			},
+			{
+				path: "io",
+				name: "io",
+			},
+		},
+		interfaces: []interfaceInfo{
+			{
+				name: "Storage",
+				methods: []methodType{
+					{
+						name: "Download",
+						params: []tupleType{
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+						},
+						results: []tupleType{
+							{
+								name:       "",
+								typeStr:    "io.ReadCloser",
+								recognized: recognizedTypeReadCloser,
+								pkgList: []tupleTypePkg{
+									{
+										path:  "io",
+										begin: 0,
+										end:   len("io"),
+									},
+								},
+							},
+							{
+								name:       "",
+								typeStr:    "error",
+								recognized: recognizedTypeError,
+							},
+						},
+					},
+				},
+			},
+		},
+	}, WithStreaming())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package example
+
+import (
+	"context"
+	"io"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/codes"
+	"github.com/QuangTung97/otelwrap/support"
+)
+
+// StorageWrapper wraps OpenTelemetry's span
+type StorageWrapper struct {
+	Storage
+	tracer trace.Tracer
+	prefix string
+}
+
+var _ Storage = (*StorageWrapper)(nil)
+
+// NewStorageWrapper creates a wrapper
+func NewStorageWrapper(wrapped Storage, tracer trace.Tracer, prefix string) *StorageWrapper {
+	return &StorageWrapper{
+		Storage: wrapped,
+		tracer: tracer,
+		prefix: prefix,
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *StorageWrapper) Unwrap() Storage {
+	return w.Storage
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *StorageWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Storage
+}
+
+// Download ...
+func (w *StorageWrapper) Download(ctx context.Context) (a io.ReadCloser, err error) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "Download")
+	streaming := false
+	defer func() {
+		if !streaming {
+			span.End()
+		}
+	}()
+
+	a, err = w.Storage.Download(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	if a != nil {
+		a = support.TraceReadCloser(span, a)
+		streaming = true
+	}
+	return a, err
+}
+`, buf.String())
+}
+
+//revive:disable:line-length-limit
+func TestGenerateCode_With_Callbacks(t *testing.T) {
+	var buf bytes.Buffer
+	err := generateCode(&buf, packageTypeInfo{
+		name: "example",
+		path: "hello/example",
+		imports: []importInfo{
+			{
+				path: "context",
+				name: "context",
+			},
+		},
+		interfaces: []interfaceInfo{
+			{
+				name: "Broker",
+				methods: []methodType{
+					{
+						name: "Subscribe",
+						params: []tupleType{
+							{
+								name:       "ctx",
+								typeStr:    "context.Context",
+								recognized: recognizedTypeContext,
+								pkgList:    pkgListContext(),
+							},
+							{
+								name:    "handler",
+								typeStr: "func(ctx context.Context, msg string) error",
+								pkgList: []tupleTypePkg{
+									{
+										path:  "context",
+										begin: len("func(ctx "),
+										end:   len("func(ctx context"),
+									},
+								},
+								signature: &signatureType{
+									params: []tupleType{
+										{
+											name:       "ctx",
+											typeStr:    "context.Context",
+											recognized: recognizedTypeContext,
+											pkgList:    pkgListContext(),
+										},
+										{
+											name:    "msg",
+											typeStr: "string",
+										},
+									},
+									results: []tupleType{
+										{
+											typeStr:    "error",
+											recognized: recognizedTypeError,
+										},
+									},
+								},
+							},
+						},
+						results: []tupleType{
+							{
+								name:      "stop",
+								typeStr:   "func()",
+								signature: &signatureType{},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, WithCallbacks())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package example
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// BrokerWrapper wraps OpenTelemetry's span
+type BrokerWrapper struct {
+	Broker
+	tracer trace.Tracer
+	prefix string
+}
+
+var _ Broker = (*BrokerWrapper)(nil)
+
+// NewBrokerWrapper creates a wrapper
+func NewBrokerWrapper(wrapped Broker, tracer trace.Tracer, prefix string) *BrokerWrapper {
+	return &BrokerWrapper{
+		Broker: wrapped,
+		tracer: tracer,
+		prefix: prefix,
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *BrokerWrapper) Unwrap() Broker {
+	return w.Broker
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *BrokerWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Broker
+}
+
+// Subscribe ...
+func (w *BrokerWrapper) Subscribe(ctx context.Context, handler func(ctx context.Context, msg string) error) (stop func()) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "Subscribe")
+	defer span.End()
+	link := trace.LinkFromContext(ctx)
+	if handler != nil {
+		callback := handler
+		handler = func(ctx context.Context, msg string) (err error) {
+			ctx, span := w.tracer.Start(ctx, w.prefix + "Subscribe.handler", trace.WithLinks(link))
+			defer span.End()
+
+			err = callback(ctx, msg)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return err
+		}
+	}
+
+	stop = w.Broker.Subscribe(ctx, handler)
+	
+	if stop != nil {
+		callback := stop
+		stop = func() {
+			_, span := w.tracer.Start(ctx, w.prefix + "Subscribe.stop")
+			defer span.End()
+
+			callback()
+		}
+	}
+	return stop
+}
+`, buf.String())
+}
+
+//revive:enable:line-length-limit
+
+func TestGenerateCode_With_Skipped_Methods(t *testing.T) {
+	var diagnostics []Diagnostic
+	var buf bytes.Buffer
+	err := LoadAndGenerate(&buf, "./hello", []string{"Cache"},
+		WithSkipMethods("Ping", "Processor.DoA"),
+		WithDiagnostics(func(d Diagnostic) {
+			diagnostics = append(diagnostics, d)
+		}),
+	)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `
+package hello
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// CacheWrapper wraps OpenTelemetry's span
+//
+// Skipped methods, not traced: Get, Ping
+type CacheWrapper struct {
+	Cache
+	tracer trace.Tracer
+	prefix string
+}
+
+var _ Cache = (*CacheWrapper)(nil)
+
+// NewCacheWrapper creates a wrapper
+func NewCacheWrapper(wrapped Cache, tracer trace.Tracer, prefix string) *CacheWrapper {
+	return &CacheWrapper{
+		Cache: wrapped,
+		tracer: tracer,
+		prefix: prefix,
+	}
+}
+
+// Unwrap returns the wrapped implementation
+func (w *CacheWrapper) Unwrap() Cache {
+	return w.Cache
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *CacheWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Cache
+}
+
+// Set ...
+func (w *CacheWrapper) Set(ctx context.Context, key string, u User) (err error) {
+	ctx, span := w.tracer.Start(ctx, w.prefix + "Set")
+	defer span.End()
+
+	err = w.Cache.Set(ctx, key, u)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+`, buf.String())
+	assert.Equal(t, []Diagnostic{
+		{Interface: "Cache", Method: "Get", Message: "not traced, skipped"},
+		{Interface: "Cache", Method: "Ping", Message: "not traced, skipped"},
+	}, diagnostics)
+}
+
+// typeCheckInPackage returns the errors of type checking the package in dir
+// with the generated source added as a file of the package
+func typeCheckInPackage(t *testing.T, dir string, source []byte) []string {
+	dir, err := filepath.Abs(dir)
+	assert.Equal(t, nil, err)
+
+	pkgList, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes,
+		Dir:  dir,
+		Overlay: map[string][]byte{
+			filepath.Join(dir, "otelwrap_generated.go"): source,
+		},
+	}, ".")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(pkgList))
+
+	var errorList []string
+	for _, pkgErr := range pkgList[0].Errors {
+		errorList = append(errorList, pkgErr.Msg)
+	}
+	return errorList
+}
+
+func TestLoadAndGenerate_Skipped_Methods_Compile(t *testing.T) {
+	var buf bytes.Buffer
+	err := LoadAndGenerate(&buf, "./hello", []string{"ExpiringCache", "Cache"}, WithSkipMethods("Set"))
+	assert.Equal(t, nil, err)
+	assert.NotContains(t, buf.String(), `"time"`)
+	assert.NotContains(t, buf.String(), `"io"`)
+	assert.Equal(t, []string(nil), typeCheckInPackage(t, "./hello", buf.Bytes()))
+
+	buf.Reset()
+	err = LoadAndGenerate(&buf, "./hello", []string{"ExpiringCache"}, WithNoEmbed())
+	assert.Equal(t, nil, err)
+	assert.Contains(t, buf.String(), `"time"`)
+	assert.Contains(t, buf.String(), `"io"`)
+	assert.Equal(t, []string(nil), typeCheckInPackage(t, "./hello", buf.Bytes()))
+}
+
+func TestLoadAndGenerate_Fallback_Context_In_Constructor(t *testing.T) {
+	var buf bytes.Buffer
+	err := LoadAndGenerate(&buf, "./hello", []string{"MixedContextHandler"}, WithAllMethods())
+	assert.Equal(t, nil, err)
+	assert.Contains(t, buf.String(), "func NewMixedContextHandlerWrapper(wrapped MixedContextHandler, "+
+		"tracer trace.Tracer, prefix string, fallbackCtx context.Context) *MixedContextHandlerWrapper {\n")
+	assert.NotContains(t, buf.String(), "SetFallbackContext")
+	assert.Equal(t, []string(nil), typeCheckInPackage(t, "./hello", buf.Bytes()))
+
+	buf.Reset()
+	err = LoadAndGenerate(&buf, "./hello", []string{"MixedContextHandler"}, WithAllMethods(), WithOptionsConstructor())
+	assert.Equal(t, nil, err)
+	assert.Contains(t, buf.String(), "\tfallbackCtx := conf.FallbackContext\n")
+	assert.NotContains(t, buf.String(), "SetFallbackContext")
+	assert.Equal(t, []string(nil), typeCheckInPackage(t, "./hello", buf.Bytes()))
+}
@@ -2,50 +2,208 @@ package main
 
 import (
 	"errors"
-	"fmt"
 	"github.com/QuangTung97/otelwrap/otelwrap"
 	"github.com/spf13/cobra"
 	"os"
 )
 
-func main() {
-	cmd := &cobra.Command{
-		Use: "otelwrap",
+// flagReader reads flags of a command, keeping the first error
+type flagReader struct {
+	cmd *cobra.Command
+	err error
+}
+
+func (r *flagReader) setErr(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *flagReader) getBool(name string) bool {
+	value, err := r.cmd.Flags().GetBool(name)
+	r.setErr(err)
+	return value
+}
+
+func (r *flagReader) getString(name string) string {
+	value, err := r.cmd.Flags().GetString(name)
+	r.setErr(err)
+	return value
+}
+
+func (r *flagReader) getStringSlice(name string) []string {
+	value, err := r.cmd.Flags().GetStringSlice(name)
+	r.setErr(err)
+	return value
+}
+
+func (r *flagReader) getStringArray(name string) []string {
+	value, err := r.cmd.Flags().GetStringArray(name)
+	r.setErr(err)
+	return value
+}
+
+// readGenerateArgs reads flags that are common for all commands
+func readGenerateArgs(cmd *cobra.Command) (otelwrap.CommandArgs, error) {
+	r := &flagReader{cmd: cmd}
+	args := otelwrap.CommandArgs{
+		Options:         r.getBool("options"),
+		CodeAttributes:  r.getBool("code-attributes"),
+		CodeLocation:    r.getBool("code-location"),
+		SpanKind:        r.getString("span-kind"),
+		AllMethods:      r.getBool("all-methods"),
+		NoEmbed:         r.getBool("no-embed"),
+		Streaming:       r.getBool("streaming"),
+		Callbacks:       r.getBool("callbacks"),
+		Metrics:         r.getBool("metrics"),
+		RecordPanics:    r.getBool("record-panics"),
+		ErrorClassifier: r.getBool("error-classifier"),
+		SkipMethods:     r.getStringSlice("skip-methods"),
+		IgnoreErrors:    r.getStringSlice("ignore-errors"),
+		TypeArgs:        r.getStringArray("type-args"),
+		Template:        r.getString("template"),
+		Check:           r.getBool("check"),
+	}
+	if r.err != nil {
+		return otelwrap.CommandArgs{}, r.err
+	}
+	return args, nil
+}
+
+func newScanCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "scan [packages]",
+		Short: "Generate wrappers for all interfaces with the //otelwrap:wrap directive",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) < 2 {
-				return errors.New("missing directory and interface list")
-			}
-			if args[0] != "." {
-				return errors.New("only support '.' as directory")
+			if len(args) == 0 {
+				args = []string{"./..."}
 			}
 
-			out, err := cmd.Flags().GetString("out")
+			commandArgs, err := readGenerateArgs(cmd)
 			if err != nil {
 				return err
 			}
-			if out == "" {
-				return errors.New("missing 'out' flag")
-			}
+			return otelwrap.RunScanCommand(args, commandArgs)
+		},
+	}
+}
+
+func newTemplateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Commands for custom templates",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "dump",
+		Short: "Print the built-in template, as a starting point for custom templates",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return otelwrap.RunTemplateDumpCommand(os.Stdout)
+		},
+	})
+	return cmd
+}
+
+// runGenerateCommand generates wrappers for the interfaces of a directory
+func runGenerateCommand(cmd *cobra.Command, args []string) error {
+	r := &flagReader{cmd: cmd}
+	match := r.getString("match")
+	exclude := r.getString("exclude")
+	out := r.getString("out")
+	pkgName := r.getString("pkg")
+	if r.err != nil {
+		return r.err
+	}
+
+	if len(args) < 1 || (len(args) < 2 && match == "") {
+		return errors.New("missing directory and interface list")
+	}
+	if out == "" {
+		return errors.New("missing 'out' flag")
+	}
 
-			pkgName, err := cmd.Flags().GetString("pkg")
+	commandArgs, err := readGenerateArgs(cmd)
+	if err != nil {
+		return err
+	}
+	commandArgs.Dir = args[0]
+	commandArgs.SrcFileName = os.Getenv("GOFILE")
+	commandArgs.InterfaceNames = args[1:]
+	commandArgs.InAnother = otelwrap.CheckInAnotherDir(args[0], out)
+	commandArgs.PkgName = pkgName
+	commandArgs.Match = match
+	commandArgs.Exclude = exclude
+
+	return otelwrap.RunCommand(commandArgs, out)
+}
+
+func main() {
+	cmd := &cobra.Command{
+		Use:  "otelwrap",
+		Args: cobra.ArbitraryArgs,
+
+		SilenceErrors: true,
+		SilenceUsage:  true,
+
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			format, err := cmd.Flags().GetString("error-format")
 			if err != nil {
 				return err
 			}
-
-			return otelwrap.RunCommand(otelwrap.CommandArgs{
-				Dir:            args[0],
-				SrcFileName:    os.Getenv("GOFILE"),
-				InterfaceNames: args[1:],
-				InAnother:      otelwrap.CheckInAnother(out),
-				PkgName:        pkgName,
-			}, out)
+			return otelwrap.CheckErrorFormat(format)
 		},
+		RunE: runGenerateCommand,
 	}
 	cmd.Flags().String("out", "", "required, output file name")
 	cmd.Flags().String("pkg", "", "package name if specified interface is in another package")
+	cmd.Flags().String("match", "",
+		"also wrap the interfaces of the package with names matching the regular expression")
+	cmd.Flags().String("exclude", "",
+		"do not wrap the interfaces selected by glob patterns or --match with names matching the regular expression")
+	cmd.PersistentFlags().Bool("options", false,
+		"generate constructors accepting options instead of a tracer and a prefix")
+	cmd.PersistentFlags().Bool("code-attributes", false,
+		"add the code.function and code.namespace attributes to every span")
+	cmd.PersistentFlags().Bool("code-location", false,
+		"also add the code.filepath and code.lineno attributes of the method declarations")
+	cmd.PersistentFlags().String("span-kind", "",
+		"span kind of methods without the kind directive: internal, server, client, producer or consumer")
+	cmd.PersistentFlags().Bool("all-methods", false,
+		"also trace methods without context.Context as the first param, printing how their contexts are obtained")
+	cmd.PersistentFlags().Bool("no-embed", false,
+		"store the implementation in a private field, forwarding the methods that are not traced")
+	cmd.PersistentFlags().Bool("streaming", false,
+		"keep the spans of methods returning readers, channels or iterators open until the results are consumed")
+	cmd.PersistentFlags().Bool("callbacks", false,
+		"run the invocations of func-typed params and results in child spans linked to the spans of the methods")
+	cmd.PersistentFlags().Bool("metrics", false, "also generate wrappers recording metrics")
+	cmd.PersistentFlags().Bool("record-panics", false,
+		"record panics of the wrapped methods as errors of the spans, then re-panic")
+	cmd.PersistentFlags().Bool("error-classifier", false,
+		"add an error classifier to the constructors of the wrappers")
+	cmd.PersistentFlags().StringSlice("skip-methods", nil,
+		"methods that are not traced, e.g. Get,Cache.Ping, the same as the skip directive")
+	cmd.PersistentFlags().StringSlice("ignore-errors", nil,
+		"sentinel errors that do not fail the spans, e.g. database/sql.ErrNoRows")
+	cmd.PersistentFlags().StringArray("type-args", nil,
+		"type arguments for instantiating a generic interface, e.g. Repo=User,int")
+	cmd.PersistentFlags().String("template", "",
+		"path of a custom template file used instead of the built-in template")
+	cmd.PersistentFlags().Bool("check", false,
+		"check that the output files are up to date instead of writing them")
+	cmd.PersistentFlags().String("error-format", otelwrap.ErrorFormatText,
+		"format of the error output, 'text' or 'json'")
+
+	cmd.AddCommand(newScanCommand())
+	cmd.AddCommand(newTemplateCommand())
 
 	err := cmd.Execute()
 	if err != nil {
-		fmt.Println("ERROR:", err)
+		format, _ := cmd.PersistentFlags().GetString("error-format")
+		if otelwrap.CheckErrorFormat(format) != nil {
+			format = otelwrap.ErrorFormatText
+		}
+		otelwrap.PrintError(os.Stderr, err, format)
+		os.Exit(1)
 	}
 }
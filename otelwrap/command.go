@@ -7,10 +7,11 @@ import (
 	"fmt"
 	"github.com/QuangTung97/otelwrap/internal/generate"
 	"github.com/QuangTung97/otelwrap/internal/generate/hello"
-	"go/format"
+	"github.com/pmezard/go-difflib/difflib"
 	"io"
+	"io/fs"
 	"os"
-	"path"
+	"path/filepath"
 	"strings"
 )
 
@@ -38,99 +39,398 @@ type HandlerAlias = hello.Handler
 type CommandArgs struct {
 	Dir            string
 	SrcFileName    string
-	InterfaceNames []string
+	InterfaceNames []string // names, glob patterns of names, e.g. Repo*, or qualified names
 	InAnother      bool
 	PkgName        string
+	Match          string // regular expression selecting the interfaces of the source package
+	Exclude        string // regular expression excluding interfaces selected by patterns
+
+	Options         bool
+	CodeAttributes  bool
+	CodeLocation    bool
+	SpanKind        string
+	AllMethods      bool
+	NoEmbed         bool
+	Streaming       bool
+	Callbacks       bool
+	Metrics         bool
+	RecordPanics    bool
+	ErrorClassifier bool
+	SkipMethods     []string // names of the form Method or Interface.Method
+	IgnoreErrors    []string
+	TypeArgs        []string
+	Template        string // path of a custom template file
+
+	Check bool // compares with the existing output files instead of writing
 }
 
-func splitPackageNameFromInterfaceNames(interfaceNames []string) (string, []string, error) {
-	values := strings.Split(interfaceNames[0], ".")
-	if len(values) == 1 {
-		for _, interfaceName := range interfaceNames[1:] {
-			values = strings.Split(interfaceName, ".")
-			if len(values) > 1 {
-				return "", nil, errors.New("can not have mixed interface names")
+// splitTypeArgs splits a comma-separated list of types, ignoring commas inside brackets
+func splitTypeArgs(s string) []string {
+	var result []string
+	depth := 0
+	begin := 0
+	for i, ch := range s {
+		switch ch {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				result = append(result, strings.TrimSpace(s[begin:i]))
+				begin = i + 1
 			}
 		}
-		return "", interfaceNames, nil
 	}
+	return append(result, strings.TrimSpace(s[begin:]))
+}
 
-	packageName := values[0]
-	result := make([]string, 0, len(interfaceNames))
-	for _, interfaceName := range interfaceNames {
-		values = strings.Split(interfaceName, ".")
-		if len(values) != 2 || values[0] != packageName {
-			return "", nil, errors.New("can not have mixed interface names")
+// parseTypeArgs parses values of the form: Interface=Type1,Type2
+func parseTypeArgs(values []string) ([]generate.Option, error) {
+	var options []generate.Option
+	for _, value := range values {
+		index := strings.IndexByte(value, '=')
+		if index <= 0 || index == len(value)-1 {
+			return nil, fmt.Errorf("invalid type args '%s', expected Interface=Type1,Type2", value)
+		}
+		interfaceName := strings.TrimSpace(value[:index])
+		if dot := strings.LastIndexByte(interfaceName, '.'); dot >= 0 {
+			interfaceName = interfaceName[dot+1:]
 		}
-		result = append(result, values[1])
+		options = append(options, generate.WithTypeArgs(interfaceName, splitTypeArgs(value[index+1:])...))
+	}
+	return options, nil
+}
+
+// flagOptions are the options added if a flag is set
+type flagOptions struct {
+	enabled bool
+	options []generate.Option
+}
+
+func (args CommandArgs) flagOptions() []flagOptions {
+	return []flagOptions{
+		{args.Options, []generate.Option{generate.WithOptionsConstructor()}},
+		{args.CodeAttributes, []generate.Option{generate.WithCodeAttributes()}},
+		{args.CodeLocation, []generate.Option{generate.WithCodeLocation()}},
+		{args.SpanKind != "", []generate.Option{generate.WithSpanKind(args.SpanKind)}},
+		{args.AllMethods, []generate.Option{generate.WithAllMethods(), generate.WithContextReport(os.Stdout)}},
+		{args.NoEmbed, []generate.Option{generate.WithNoEmbed()}},
+		{args.Streaming, []generate.Option{generate.WithStreaming()}},
+		{args.Callbacks, []generate.Option{generate.WithCallbacks()}},
+		{args.Metrics, []generate.Option{generate.WithMetrics()}},
+		{args.RecordPanics, []generate.Option{generate.WithRecordPanics()}},
+		{args.ErrorClassifier, []generate.Option{generate.WithErrorClassifier()}},
+		{len(args.SkipMethods) > 0, []generate.Option{generate.WithSkipMethods(args.SkipMethods...)}},
+		{len(args.IgnoreErrors) > 0, []generate.Option{generate.WithIgnoreErrors(args.IgnoreErrors...)}},
+		{args.Match != "", []generate.Option{generate.WithMatch(args.Match)}},
+		{args.Exclude != "", []generate.Option{generate.WithExclude(args.Exclude)}},
 	}
-	return packageName, result, nil
+}
+
+func (args CommandArgs) generateOptions() ([]generate.Option, error) {
+	options, err := parseTypeArgs(args.TypeArgs)
+	if err != nil {
+		return nil, generate.WrapError(err, generate.StageArgs, "")
+	}
+	for _, flag := range args.flagOptions() {
+		if flag.enabled {
+			options = append(options, flag.options...)
+		}
+	}
+	if args.Template != "" {
+		data, err := os.ReadFile(args.Template)
+		if err != nil {
+			return nil, generate.WrapError(err, generate.StageArgs, "")
+		}
+		options = append(options, generate.WithTemplate(string(data)))
+	}
+	options = append(options, generate.WithMatchReport(os.Stdout))
+	return options, nil
+}
+
+// packagePattern returns the pattern for loading the package in the directory
+func packagePattern(dir string) string {
+	if filepath.IsAbs(dir) || strings.HasPrefix(dir, ".") {
+		return dir
+	}
+	return "./" + dir
+}
+
+// findPackage finds the package imported with the name, first in the source file of go:generate
+// if the directory is the current one, then in any file of the directory
+func findPackage(args CommandArgs, packageName string) (generate.FindResult, error) {
+	if args.SrcFileName != "" && filepath.Clean(args.Dir) == "." {
+		result, err := generate.FindPackage(args.SrcFileName, packageName)
+		if !errors.Is(err, generate.ErrNotFound) {
+			return result, err
+		}
+	}
+	return generate.FindPackageInDir(args.Dir, packageName)
+}
+
+// interfacePackage is the package of qualified interface names,
+// an import path or a package name imported by the source files
+type interfacePackage struct {
+	pattern string // empty for interfaces of the source package
+	srcName string // name of the source package found with the imported package name
+}
+
+// splitInterfaceName splits a name of the form Name, alias.Name or github.com/acme/lib/store.Name
+func splitInterfaceName(interfaceName string) (qualifier string, name string, err error) {
+	dot := strings.LastIndexByte(interfaceName, '.')
+	if dot < 0 {
+		return "", interfaceName, nil
+	}
+	qualifier, name = interfaceName[:dot], interfaceName[dot+1:]
+	if qualifier == "" || name == "" || strings.HasSuffix(qualifier, "/") {
+		return "", "", fmt.Errorf("invalid interface name '%s'", interfaceName)
+	}
+	if !strings.Contains(qualifier, "/") && strings.Contains(qualifier, ".") {
+		return "", "", fmt.Errorf("invalid interface name '%s', expected Name, alias.Name or path.Name", interfaceName)
+	}
+	return qualifier, name, nil
+}
+
+func resolveInterfacePackage(args CommandArgs, qualifier string) (interfacePackage, error) {
+	if qualifier == "" || strings.Contains(qualifier, "/") {
+		return interfacePackage{pattern: qualifier}, nil
+	}
+
+	findResult, err := findPackage(args, qualifier)
+	if err != nil {
+		return interfacePackage{}, generate.WrapError(
+			fmt.Errorf("package '%s' in directory '%s': %w", qualifier, args.Dir, err),
+			generate.StageFind, "")
+	}
+	return interfacePackage{
+		pattern: findResult.DestPkgPath,
+		srcName: findResult.SrcPkgName,
+	}, nil
+}
+
+// hasLocalInterfaces checks whether any interface is selected in the source package
+func hasLocalInterfaces(args CommandArgs) (bool, error) {
+	hasLocal := args.Match != ""
+	for _, interfaceName := range args.InterfaceNames {
+		qualifier, _, err := splitInterfaceName(interfaceName)
+		if err != nil {
+			return false, generate.WrapError(err, generate.StageArgs, "")
+		}
+		if qualifier == "" {
+			hasLocal = true
+		}
+	}
+	return hasLocal, nil
+}
+
+// groupInterfaceNames groups the interface names by their packages, in the order of appearance,
+// the interfaces of the source package first
+func groupInterfaceNames(args CommandArgs) ([]generate.InterfaceGroup, string, error) {
+	var groups []generate.InterfaceGroup
+	groupIndex := map[string]int{}
+	srcPkgName := ""
+
+	hasLocal, err := hasLocalInterfaces(args)
+	if err != nil {
+		return nil, "", err
+	}
+	if hasLocal {
+		groups = append(groups, generate.InterfaceGroup{Pattern: packagePattern(args.Dir)})
+		groupIndex[""] = 0
+	}
+
+	resolved := map[string]interfacePackage{}
+	for _, interfaceName := range args.InterfaceNames {
+		qualifier, name, _ := splitInterfaceName(interfaceName)
+
+		pkg, ok := resolved[qualifier]
+		if !ok {
+			pkg, err = resolveInterfacePackage(args, qualifier)
+			if err != nil {
+				return nil, "", err
+			}
+			resolved[qualifier] = pkg
+			if srcPkgName == "" {
+				srcPkgName = pkg.srcName
+			}
+		}
+
+		index, ok := groupIndex[pkg.pattern]
+		if !ok {
+			index = len(groups)
+			groupIndex[pkg.pattern] = index
+			groups = append(groups, generate.InterfaceGroup{Pattern: pkg.pattern})
+		}
+		groups[index].InterfaceNames = append(groups[index].InterfaceNames, name)
+	}
+	return groups, srcPkgName, nil
 }
 
 func findAndGenerate(w io.Writer, args CommandArgs) error {
-	packageName, interfaceNames, err := splitPackageNameFromInterfaceNames(args.InterfaceNames)
+	groups, srcPkgName, err := groupInterfaceNames(args)
+	if err != nil {
+		return err
+	}
+
+	options, err := args.generateOptions()
 	if err != nil {
-		fmt.Println("splitPackageNameFromInterfaceNames", err)
 		return err
 	}
 
-	if len(packageName) == 0 {
+	if groups[0].Pattern == packagePattern(args.Dir) {
 		if args.InAnother {
-			return generate.LoadAndGenerate(w,
-				".", interfaceNames,
-				generate.WithInAnotherPackage(args.PkgName),
-			)
+			options = append(options, generate.WithInAnotherPackage(args.PkgName))
 		}
-		return generate.LoadAndGenerate(w,
-			".", interfaceNames,
-		)
+		return generate.LoadAndGenerateGroups(w, groups, options...)
 	}
 
-	filePath := path.Join(args.Dir, args.SrcFileName)
-	findResult, err := generate.FindPackage(filePath, packageName)
-	if err != nil {
-		fmt.Println("FindPackage", err)
-		return err
+	// all interfaces are in other packages, the output package is the package in the directory
+	// if the output file is not in another directory
+	pkgName := srcPkgName
+	if args.InAnother && args.PkgName != "" {
+		pkgName = args.PkgName
+	}
+	if pkgName == "" {
+		pkgName, err = generate.FindPackageName(args.Dir)
+		if err != nil {
+			return generate.WrapError(fmt.Errorf("package name of directory '%s': %w", args.Dir, err),
+				generate.StageFind, "")
+		}
 	}
+	options = append(options, generate.WithInAnotherPackage(pkgName))
+	return generate.LoadAndGenerateGroups(w, groups, options...)
+}
+
+// ErrOutdated is returned in check mode when a generated file is not up to date
+var ErrOutdated = errors.New("generated file is outdated")
 
-	return generate.LoadAndGenerate(w,
-		findResult.DestPkgPath, interfaceNames,
-		generate.WithInAnotherPackage(findResult.SrcPkgName),
-	)
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
 }
 
-// RunCommand ...
-func RunCommand(args CommandArgs, outFile string) error {
-	var buf bytes.Buffer
-	_, _ = buf.WriteString(`// Code generated by otelwrap; DO NOT EDIT.
-// github.com/QuangTung97/otelwrap
+// checkGeneratedFile compares the existing output file with the generated data,
+// writing the unified diff of the difference to w
+func checkGeneratedFile(w io.Writer, outFile string, data []byte) error {
+	existing, err := os.ReadFile(outFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if bytes.Equal(existing, data) {
+		return nil
+	}
 
-`)
-	err := findAndGenerate(&buf, args)
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(string(existing)),
+		B:        splitLines(string(data)),
+		FromFile: outFile,
+		ToFile:   outFile + " (generated)",
+		Context:  3,
+	})
 	if err != nil {
 		return err
 	}
+	_, _ = io.WriteString(w, diff)
 
-	data, err := format.Source(buf.Bytes())
+	return fmt.Errorf("%w: %s", ErrOutdated, outFile)
+}
+
+//revive:disable-next-line:flag-parameter
+func writeGeneratedFile(outFile string, check bool, generateFunc func(w io.Writer) error) error {
+	data, err := generate.Render(generateFunc)
 	if err != nil {
-		fmt.Println("format.Source", string(buf.Bytes()), err)
 		return err
 	}
 
+	if check {
+		return generate.WrapError(checkGeneratedFile(os.Stdout, outFile, data), generate.StageCheck, "")
+	}
+
 	file, err := os.Create(outFile)
 	if err != nil {
-		return err
+		return generate.WrapError(err, generate.StageWrite, "")
 	}
 	defer func() {
 		_ = file.Close()
 	}()
 
 	_, err = file.Write(data)
+	return generate.WrapError(err, generate.StageWrite, "")
+}
+
+// RunCommand ...
+func RunCommand(args CommandArgs, outFile string) error {
+	if args.InAnother && args.PkgName == "" {
+		pkgName, err := outputPackageName(outFile)
+		if err != nil {
+			return err
+		}
+		args.PkgName = pkgName
+	}
+	return writeGeneratedFile(outFile, args.Check, func(w io.Writer) error {
+		return findAndGenerate(w, args)
+	})
+}
+
+// outputPackageName returns the name of the package in the directory of the output file
+func outputPackageName(outFile string) (string, error) {
+	dir := filepath.Dir(outFile)
+	pkgName, err := generate.FindPackageName(dir)
+	if err != nil {
+		return "", generate.WrapError(
+			fmt.Errorf("package name of output directory '%s', specify it with --pkg: %w", dir, err),
+			generate.StageFind, "")
+	}
+	return pkgName, nil
+}
+
+// RunScanCommand generates wrappers for all interfaces with the wrap directive in the packages matching the patterns
+func RunScanCommand(patterns []string, args CommandArgs) error {
+	options, err := args.generateOptions()
+	if err != nil {
+		return err
+	}
+
+	scanner, err := generate.Scan(patterns...)
+	if err != nil {
+		return err
+	}
+
+	var outdatedErr error
+	for _, target := range scanner.Targets {
+		err := writeGeneratedFile(target.OutFile, args.Check, func(w io.Writer) error {
+			return scanner.Generate(w, target, options...)
+		})
+		if errors.Is(err, ErrOutdated) {
+			outdatedErr = err
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !args.Check {
+			fmt.Printf("%s: %s\n", target.OutFile, strings.Join(target.InterfaceNames, ", "))
+		}
+	}
+	return outdatedErr
+}
+
+// RunTemplateDumpCommand writes the built-in template, as a starting point for custom templates
+func RunTemplateDumpCommand(w io.Writer) error {
+	_, err := io.WriteString(w, generate.DefaultTemplate())
 	return err
 }
 
 // CheckInAnother ...
 func CheckInAnother(filename string) bool {
-	dir := path.Dir(filename)
-	return dir != "."
+	return CheckInAnotherDir(".", filename)
+}
+
+// CheckInAnotherDir returns true if the output file is not in the directory of the source package
+func CheckInAnotherDir(dir string, filename string) bool {
+	return filepath.Clean(filepath.Dir(filename)) != filepath.Clean(dir)
 }
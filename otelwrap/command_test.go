@@ -4,8 +4,13 @@ import (
 	"bytes"
 	_ "embed"
 	"errors"
+	"fmt"
+	"github.com/QuangTung97/otelwrap/internal/generate"
 	"github.com/QuangTung97/otelwrap/internal/generate/hello"
 	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -38,6 +43,8 @@ type SimpleWrapper struct {
 	prefix string
 }
 
+var _ hello.Simple = (*SimpleWrapper)(nil)
+
 // NewSimpleWrapper creates a wrapper
 func NewSimpleWrapper(wrapped hello.Simple, tracer trace.Tracer, prefix string) *SimpleWrapper {
 	return &SimpleWrapper{
@@ -47,6 +54,19 @@ func NewSimpleWrapper(wrapped hello.Simple, tracer trace.Tracer, prefix string)
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *SimpleWrapper) Unwrap() hello.Simple {
+	return w.Simple
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *SimpleWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Simple
+}
+
 // Scan ...
 func (w *SimpleWrapper) Scan(ctx context.Context, n int) (err error) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "Scan")
@@ -107,7 +127,11 @@ func TestFindAndGenerate_Same_Package_Not_Found(t *testing.T) {
 		SrcFileName:    "command_test.go",
 		InterfaceNames: []string{"Example"},
 	})
-	assert.Equal(t, errors.New("can not find interface 'Example'"), err)
+	assert.Equal(t, &generate.Error{
+		Stage:     generate.StageFind,
+		Interface: "Example",
+		Err:       errors.New("can not find interface 'Example'"),
+	}, err)
 }
 
 func TestFindAndGenerate_Same_Package_OK(t *testing.T) {
@@ -134,6 +158,8 @@ type SampleWrapper struct {
 	prefix string
 }
 
+var _ Sample = (*SampleWrapper)(nil)
+
 // NewSampleWrapper creates a wrapper
 func NewSampleWrapper(wrapped Sample, tracer trace.Tracer, prefix string) *SampleWrapper {
 	return &SampleWrapper{
@@ -143,6 +169,19 @@ func NewSampleWrapper(wrapped Sample, tracer trace.Tracer, prefix string) *Sampl
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *SampleWrapper) Unwrap() Sample {
+	return w.Sample
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *SampleWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Sample
+}
+
 // Get ...
 func (w *SampleWrapper) Get(ctx context.Context) (a int, err error) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "Get")
@@ -185,6 +224,8 @@ type SampleWrapper struct {
 	prefix string
 }
 
+var _ otelwrap.Sample = (*SampleWrapper)(nil)
+
 // NewSampleWrapper creates a wrapper
 func NewSampleWrapper(wrapped otelwrap.Sample, tracer trace.Tracer, prefix string) *SampleWrapper {
 	return &SampleWrapper{
@@ -194,6 +235,19 @@ func NewSampleWrapper(wrapped otelwrap.Sample, tracer trace.Tracer, prefix strin
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *SampleWrapper) Unwrap() otelwrap.Sample {
+	return w.Sample
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *SampleWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Sample
+}
+
 // Get ...
 func (w *SampleWrapper) Get(ctx context.Context) (a int, err error) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "Get")
@@ -214,6 +268,8 @@ type RepoWrapper struct {
 	prefix string
 }
 
+var _ otelwrap.Repo = (*RepoWrapper)(nil)
+
 // NewRepoWrapper creates a wrapper
 func NewRepoWrapper(wrapped otelwrap.Repo, tracer trace.Tracer, prefix string) *RepoWrapper {
 	return &RepoWrapper{
@@ -223,6 +279,19 @@ func NewRepoWrapper(wrapped otelwrap.Repo, tracer trace.Tracer, prefix string) *
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *RepoWrapper) Unwrap() otelwrap.Repo {
+	return w.Repo
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *RepoWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.Repo
+}
+
 // Update ...
 func (w *RepoWrapper) Update(ctx context.Context, id int) (err error) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "Update")
@@ -250,6 +319,87 @@ func TestCheckInAnother(t *testing.T) {
 	assert.Equal(t, false, inAnother)
 }
 
+func TestCheckInAnotherDir(t *testing.T) {
+	assert.Equal(t, false, CheckInAnotherDir("./internal/repo", "internal/repo/wrappers.go"))
+	assert.Equal(t, false, CheckInAnotherDir("internal/repo", "./internal/repo/wrappers.go"))
+	assert.Equal(t, true, CheckInAnotherDir("./internal/repo", "wrappers.go"))
+	assert.Equal(t, true, CheckInAnotherDir(".", "internal/repo/wrappers.go"))
+}
+
+func TestFindAndGenerate_In_Another_Dir(t *testing.T) {
+	var buf bytes.Buffer
+	err := findAndGenerate(&buf, CommandArgs{
+		Dir:            "../internal/generate/hello",
+		InterfaceNames: []string{"Simple"},
+	})
+	assert.Equal(t, nil, err)
+	assert.Contains(t, buf.String(), "\npackage hello\n")
+	assert.Contains(t, buf.String(), "\ntype SimpleWrapper struct {\n\tSimple\n")
+}
+
+func TestFindAndGenerate_In_Another_Dir_With_Package_Name(t *testing.T) {
+	var buf bytes.Buffer
+	err := findAndGenerate(&buf, CommandArgs{
+		Dir:            "../internal/generate/hello",
+		SrcFileName:    "command_test.go",
+		InterfaceNames: []string{"embed.Parser"},
+	})
+	assert.Equal(t, nil, err)
+	assert.Contains(t, buf.String(), "\npackage hello\n")
+	assert.Contains(t, buf.String(), "\ntype ParserWrapper struct {\n\tembed.Parser\n")
+}
+
+func TestFindAndGenerate_In_Another_Dir_Package_Not_Found(t *testing.T) {
+	var buf bytes.Buffer
+	err := findAndGenerate(&buf, CommandArgs{
+		Dir:            "../internal/generate/hello",
+		InterfaceNames: []string{"random.Parser"},
+	})
+	assert.Equal(t, &generate.Error{
+		Stage: generate.StageFind,
+		Err: fmt.Errorf("package 'random' in directory '../internal/generate/hello': %w",
+			generate.ErrNotFound),
+	}, err)
+}
+
+func TestRunCommand_Output_In_Another_Dir(t *testing.T) {
+	outDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(outDir, "main.go"), []byte("package main\n"), 0600)
+	assert.Equal(t, nil, err)
+
+	outFile := filepath.Join(outDir, "wrappers.go")
+	err = RunCommand(CommandArgs{
+		Dir:            "../internal/generate/hello",
+		InterfaceNames: []string{"Simple"},
+		InAnother:      CheckInAnotherDir("../internal/generate/hello", outFile),
+	}, outFile)
+	assert.Equal(t, nil, err)
+
+	data, err := os.ReadFile(outFile)
+	assert.Equal(t, nil, err)
+	assert.Contains(t, string(data), "\npackage main\n")
+	assert.Contains(t, string(data), "\t\"github.com/QuangTung97/otelwrap/internal/generate/hello\"\n")
+	assert.Contains(t, string(data), "\ntype SimpleWrapper struct {\n\thello.Simple\n")
+}
+
+func TestRunCommand_Output_In_Another_Dir_Without_Package(t *testing.T) {
+	outDir := t.TempDir()
+	outFile := filepath.Join(outDir, "wrappers.go")
+	err := RunCommand(CommandArgs{
+		Dir:            "../internal/generate/hello",
+		InterfaceNames: []string{"Simple"},
+		InAnother:      true,
+	}, outFile)
+	assert.Equal(t, &generate.Error{
+		Stage: generate.StageFind,
+		Err: fmt.Errorf("package name of output directory '%s', specify it with --pkg: %w",
+			outDir, generate.ErrNotFound),
+	}, err)
+
+	_, err = os.Stat(outFile)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
 func TestFindAndGenerate_Alias_Of_Another_Package(t *testing.T) {
 	var buf bytes.Buffer
 	err := findAndGenerate(&buf, CommandArgs{
@@ -274,6 +424,8 @@ type HandlerAliasWrapper struct {
 	prefix string
 }
 
+var _ HandlerAlias = (*HandlerAliasWrapper)(nil)
+
 // NewHandlerAliasWrapper creates a wrapper
 func NewHandlerAliasWrapper(wrapped HandlerAlias, tracer trace.Tracer, prefix string) *HandlerAliasWrapper {
 	return &HandlerAliasWrapper{
@@ -283,6 +435,19 @@ func NewHandlerAliasWrapper(wrapped HandlerAlias, tracer trace.Tracer, prefix st
 	}
 }
 
+// Unwrap returns the wrapped implementation
+func (w *HandlerAliasWrapper) Unwrap() HandlerAlias {
+	return w.HandlerAlias
+}
+
+// OtelwrapUnwrap returns the wrapped implementation, for UnwrapAll of the otelwrap support package
+func (w *HandlerAliasWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.HandlerAlias
+}
+
 // Process ...
 func (w *HandlerAliasWrapper) Process(ctx context.Context, n int) (err error) {
 	ctx, span := w.tracer.Start(ctx, w.prefix + "Process")
@@ -313,3 +478,184 @@ func TestFindAndGenerate_Interface_With_Generic_Types(t *testing.T) {
 
 	assert.Equal(t, "\n"+genericHandlerData, buf.String())
 }
+
+//go:embed testdata/generic_repo
+var genericRepoData string
+
+func TestFindAndGenerate_Generic_Interface(t *testing.T) {
+	var buf bytes.Buffer
+	err := findAndGenerate(&buf, CommandArgs{
+		Dir:            ".",
+		SrcFileName:    "command_test.go",
+		InterfaceNames: []string{"hello.GenericRepo"},
+	})
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, "\n"+genericRepoData, buf.String())
+}
+
+//go:embed testdata/generic_repo_instantiated
+var genericRepoInstantiatedData string
+
+func TestFindAndGenerate_Generic_Interface_With_Type_Args(t *testing.T) {
+	var buf bytes.Buffer
+	err := findAndGenerate(&buf, CommandArgs{
+		Dir:            ".",
+		SrcFileName:    "command_test.go",
+		InterfaceNames: []string{"hello.GenericRepo"},
+		TypeArgs:       []string{"hello.GenericRepo=*User, otelgosdk.Content"},
+	})
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, "\n"+genericRepoInstantiatedData, buf.String())
+}
+
+func TestFindAndGenerate_Import_Path(t *testing.T) {
+	var buf bytes.Buffer
+	err := findAndGenerate(&buf, CommandArgs{
+		Dir:            ".",
+		InterfaceNames: []string{"github.com/QuangTung97/otelwrap/internal/generate/hello/otel/sdk.Keyer"},
+	})
+	assert.Equal(t, nil, err)
+	assert.Contains(t, buf.String(), "\npackage otelwrap\n")
+	assert.Contains(t, buf.String(), "\n\t\"github.com/QuangTung97/otelwrap/internal/generate/hello/otel/sdk\"\n")
+	assert.Contains(t, buf.String(), "\ntype KeyerWrapper struct {\n\totelgo.Keyer\n")
+}
+
+func TestFindAndGenerate_Mixed_Packages(t *testing.T) {
+	var buf bytes.Buffer
+	err := findAndGenerate(&buf, CommandArgs{
+		Dir:         ".",
+		SrcFileName: "command_test.go",
+		InterfaceNames: []string{
+			"github.com/QuangTung97/otelwrap/internal/generate/hello/otel/sdk.Keyer",
+			"Repo",
+			"hello.Simple",
+		},
+	})
+	assert.Equal(t, nil, err)
+
+	source := buf.String()
+	assert.Contains(t, source, "\npackage otelwrap\n")
+	assert.Contains(t, source, "\ntype RepoWrapper struct {\n\tRepo\n")
+	assert.Contains(t, source, "\ntype KeyerWrapper struct {\n\totelgo.Keyer\n")
+	assert.Contains(t, source, "\ntype SimpleWrapper struct {\n\thello.Simple\n")
+	assert.Less(t, strings.Index(source, "RepoWrapper"), strings.Index(source, "KeyerWrapper"))
+	assert.Less(t, strings.Index(source, "KeyerWrapper"), strings.Index(source, "SimpleWrapper"))
+}
+
+func TestFindAndGenerate_Mixed_Packages_Conflicted_Names(t *testing.T) {
+	var buf bytes.Buffer
+	err := findAndGenerate(&buf, CommandArgs{
+		Dir: ".",
+		InterfaceNames: []string{
+			"github.com/QuangTung97/otelwrap/internal/generate/hello/otel/sdk.Keyer",
+			"github.com/QuangTung97/otelwrap/internal/generate/hello.Processor",
+		},
+	})
+	assert.Equal(t, nil, err)
+	assert.Contains(t, buf.String(), "\n\thotelgo \"github.com/QuangTung97/otelwrap/internal/generate/hello/otel\"\n")
+	assert.Contains(t, buf.String(), "\ntype KeyerWrapper struct {\n\totelgo.Keyer\n")
+}
+
+func TestFindAndGenerate_Invalid_Interface_Name(t *testing.T) {
+	var buf bytes.Buffer
+	err := findAndGenerate(&buf, CommandArgs{
+		Dir:            ".",
+		InterfaceNames: []string{"Repo", "hello.embed.Parser"},
+	})
+	assert.Equal(t, &generate.Error{
+		Stage: generate.StageArgs,
+		Err:   errors.New("invalid interface name 'hello.embed.Parser', expected Name, alias.Name or path.Name"),
+	}, err)
+}
+
+func TestFindAndGenerate_Match_And_Exclude(t *testing.T) {
+	var buf bytes.Buffer
+	err := findAndGenerate(&buf, CommandArgs{
+		Dir:     ".",
+		Match:   ".*",
+		Exclude: "Alias$",
+	})
+	assert.Equal(t, nil, err)
+
+	source := buf.String()
+	assert.Contains(t, source, "\ntype RepoWrapper struct {\n\tRepo\n")
+	assert.Contains(t, source, "\ntype SampleWrapper struct {\n\tSample\n")
+	assert.NotContains(t, source, "HandlerAliasWrapper")
+	assert.Less(t, strings.Index(source, "RepoWrapper"), strings.Index(source, "SampleWrapper"))
+}
+
+func TestFindAndGenerate_Glob_Pattern(t *testing.T) {
+	var buf bytes.Buffer
+	err := findAndGenerate(&buf, CommandArgs{
+		Dir:            ".",
+		InterfaceNames: []string{"Sa*"},
+	})
+	assert.Equal(t, nil, err)
+	assert.Contains(t, buf.String(), "\ntype SampleWrapper struct {\n\tSample\n")
+	assert.NotContains(t, buf.String(), "RepoWrapper")
+}
+
+func TestSplitInterfaceName(t *testing.T) {
+	table := []struct {
+		name      string
+		input     string
+		qualifier string
+		output    string
+		err       error
+	}{
+		{name: "local", input: "Repo", output: "Repo"},
+		{name: "alias", input: "hello.Simple", qualifier: "hello", output: "Simple"},
+		{
+			name:      "import path",
+			input:     "github.com/acme/lib/store.Store",
+			qualifier: "github.com/acme/lib/store",
+			output:    "Store",
+		},
+		{name: "missing name", input: "hello.", err: errors.New("invalid interface name 'hello.'")},
+		{name: "missing package", input: ".Repo", err: errors.New("invalid interface name '.Repo'")},
+		{
+			name:  "missing path base",
+			input: "github.com/acme/.Repo",
+			err:   errors.New("invalid interface name 'github.com/acme/.Repo'"),
+		},
+	}
+	for _, e := range table {
+		t.Run(e.name, func(t *testing.T) {
+			qualifier, output, err := splitInterfaceName(e.input)
+			assert.Equal(t, e.err, err)
+			assert.Equal(t, e.qualifier, qualifier)
+			assert.Equal(t, e.output, output)
+		})
+	}
+}
+
+func TestSplitTypeArgs(t *testing.T) {
+	assert.Equal(t, []string{"int"}, splitTypeArgs("int"))
+	assert.Equal(t, []string{"User", "map[string]int"}, splitTypeArgs("User, map[string]int"))
+	assert.Equal(t, []string{"Pair[int, string]", "func(a, b int)"}, splitTypeArgs("Pair[int, string],func(a, b int)"))
+}
+
+func TestCheckGeneratedFile(t *testing.T) {
+	outFile := filepath.Join(t.TempDir(), "wrappers.go")
+
+	var buf bytes.Buffer
+	err := checkGeneratedFile(&buf, outFile, []byte("package example\n"))
+	assert.True(t, errors.Is(err, ErrOutdated))
+	assert.Equal(t, "--- "+outFile+"\n+++ "+outFile+" (generated)\n@@ -0,0 +1 @@\n+package example\n", buf.String())
+
+	err = os.WriteFile(outFile, []byte("package example\n\ntype A int\n"), 0600)
+	assert.Equal(t, nil, err)
+
+	buf.Reset()
+	err = checkGeneratedFile(&buf, outFile, []byte("package example\n\ntype B int\n"))
+	assert.True(t, errors.Is(err, ErrOutdated))
+	assert.Equal(t, "--- "+outFile+"\n+++ "+outFile+" (generated)\n@@ -1,3 +1,3 @@\n"+
+		" package example\n \n-type A int\n+type B int\n", buf.String())
+
+	buf.Reset()
+	err = checkGeneratedFile(&buf, outFile, []byte("package example\n\ntype A int\n"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "", buf.String())
+}
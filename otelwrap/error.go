@@ -0,0 +1,58 @@
+package otelwrap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/QuangTung97/otelwrap/internal/generate"
+	"io"
+)
+
+const (
+	// ErrorFormatText prints errors as a single line of text
+	ErrorFormatText = "text"
+	// ErrorFormatJSON prints errors as a JSON object, for editors and CI
+	ErrorFormatJSON = "json"
+)
+
+type jsonError struct {
+	Stage     string `json:"stage,omitempty"`
+	Interface string `json:"interface,omitempty"`
+	File      string `json:"file,omitempty"`
+	Line      int    `json:"line,omitempty"`
+	Column    int    `json:"column,omitempty"`
+	Message   string `json:"message"`
+}
+
+func newJSONError(err error) jsonError {
+	var genErr *generate.Error
+	if !errors.As(err, &genErr) {
+		return jsonError{Message: err.Error()}
+	}
+	return jsonError{
+		Stage:     string(genErr.Stage),
+		Interface: genErr.Interface,
+		File:      genErr.Position.Filename,
+		Line:      genErr.Position.Line,
+		Column:    genErr.Position.Column,
+		Message:   genErr.Err.Error(),
+	}
+}
+
+// CheckErrorFormat returns an error if the error format is not supported
+func CheckErrorFormat(format string) error {
+	if format != ErrorFormatText && format != ErrorFormatJSON {
+		return fmt.Errorf("invalid error format '%s', expected '%s' or '%s'",
+			format, ErrorFormatText, ErrorFormatJSON)
+	}
+	return nil
+}
+
+// PrintError writes the error to w in the specified format
+func PrintError(w io.Writer, err error, format string) {
+	if format == ErrorFormatJSON {
+		_ = json.NewEncoder(w).Encode(newJSONError(err))
+		return
+	}
+	_, _ = fmt.Fprintln(w, "ERROR:", err)
+}
@@ -0,0 +1,38 @@
+package otelwrap
+
+import (
+	"bytes"
+	"errors"
+	"github.com/QuangTung97/otelwrap/internal/generate"
+	"github.com/stretchr/testify/assert"
+	"go/token"
+	"testing"
+)
+
+func TestPrintError(t *testing.T) {
+	err := &generate.Error{
+		Stage:     generate.StageFind,
+		Interface: "Repo",
+		Position: token.Position{
+			Filename: "repo.go",
+			Line:     12,
+			Column:   2,
+		},
+		Err: errors.New("param 'id' not found"),
+	}
+
+	var buf bytes.Buffer
+	PrintError(&buf, err, ErrorFormatText)
+	assert.Equal(t, "ERROR: find interface 'Repo' at repo.go:12:2: param 'id' not found\n", buf.String())
+
+	buf.Reset()
+	PrintError(&buf, err, ErrorFormatJSON)
+	assert.Equal(t,
+		`{"stage":"find","interface":"Repo","file":"repo.go","line":12,"column":2,`+
+			`"message":"param 'id' not found"}`+"\n",
+		buf.String())
+
+	buf.Reset()
+	PrintError(&buf, errors.New("missing 'out' flag"), ErrorFormatJSON)
+	assert.Equal(t, `{"message":"missing 'out' flag"}`+"\n", buf.String())
+}
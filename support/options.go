@@ -0,0 +1,105 @@
+package support
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanNameFormatter computes the span name of a method of a wrapped interface
+type SpanNameFormatter func(iface string, method string) string
+
+// DefaultSpanNameFormatter returns span names of the form: Interface.Method
+func DefaultSpanNameFormatter(iface string, method string) string {
+	return iface + "." + method
+}
+
+// Config is the configuration of a wrapper generated with the options constructor
+type Config struct {
+	Tracer            trace.Tracer
+	SpanNameFormatter SpanNameFormatter
+	SpanStartOptions  []trace.SpanStartOption
+	ErrorClassifier   ErrorClassifier // nil if not specified
+	FallbackContext   context.Context // nil if not specified
+}
+
+type options struct {
+	provider trace.TracerProvider
+	tracer   trace.Tracer
+
+	formatter     SpanNameFormatter
+	startOptions  []trace.SpanStartOption
+	errClassifier ErrorClassifier
+	fallbackCtx   context.Context
+}
+
+// Option configures a wrapper generated with the options constructor
+type Option func(opts *options)
+
+// WithTracerProvider specifies the tracer provider, default is otel.GetTracerProvider()
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(opts *options) {
+		opts.provider = provider
+	}
+}
+
+// WithTracer specifies the tracer, overriding the tracer provider
+func WithTracer(tracer trace.Tracer) Option {
+	return func(opts *options) {
+		opts.tracer = tracer
+	}
+}
+
+// WithSpanNameFormatter specifies how span names are computed, default is DefaultSpanNameFormatter
+func WithSpanNameFormatter(formatter SpanNameFormatter) Option {
+	return func(opts *options) {
+		opts.formatter = formatter
+	}
+}
+
+// WithSpanStartOptions adds options used when starting every span
+func WithSpanStartOptions(startOptions ...trace.SpanStartOption) Option {
+	return func(opts *options) {
+		opts.startOptions = append(opts.startOptions, startOptions...)
+	}
+}
+
+// WithErrorClassifier specifies the error classifier, for wrappers generated with an error classifier
+func WithErrorClassifier(classifier ErrorClassifier) Option {
+	return func(opts *options) {
+		opts.errClassifier = classifier
+	}
+}
+
+// WithFallbackContext specifies the parent context of the spans of methods without a context,
+// for wrappers generated with the all-methods mode, default is context.Background()
+func WithFallbackContext(ctx context.Context) Option {
+	return func(opts *options) {
+		opts.fallbackCtx = ctx
+	}
+}
+
+// NewConfig computes the configuration of a wrapper,
+// scopeName is the instrumentation scope name used when creating the tracer from the tracer provider
+func NewConfig(scopeName string, opts ...Option) Config {
+	result := options{
+		provider:  otel.GetTracerProvider(),
+		formatter: DefaultSpanNameFormatter,
+	}
+	for _, o := range opts {
+		o(&result)
+	}
+
+	tracer := result.tracer
+	if tracer == nil {
+		tracer = result.provider.Tracer(scopeName)
+	}
+
+	return Config{
+		Tracer:            tracer,
+		SpanNameFormatter: result.formatter,
+		SpanStartOptions:  result.startOptions,
+		ErrorClassifier:   result.errClassifier,
+		FallbackContext:   result.fallbackCtx,
+	}
+}
@@ -0,0 +1,41 @@
+package support
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"testing"
+)
+
+func TestNewConfig_Default(t *testing.T) {
+	conf := NewConfig("example.com/repo")
+
+	assert.NotNil(t, conf.Tracer)
+	assert.Equal(t, "Repo.Get", conf.SpanNameFormatter("Repo", "Get"))
+	assert.Nil(t, conf.SpanStartOptions)
+	assert.Nil(t, conf.ErrorClassifier)
+	assert.Nil(t, conf.FallbackContext)
+}
+
+func TestNewConfig_With_Options(t *testing.T) {
+	tracer := noop.NewTracerProvider().Tracer("custom")
+
+	conf := NewConfig("example.com/repo",
+		WithTracerProvider(noop.NewTracerProvider()),
+		WithTracer(tracer),
+		WithSpanNameFormatter(func(iface string, method string) string {
+			return "repo/" + method
+		}),
+		WithSpanStartOptions(trace.WithSpanKind(trace.SpanKindClient)),
+		WithErrorClassifier(DefaultErrorClassifier),
+		WithFallbackContext(context.TODO()),
+	)
+
+	assert.Equal(t, tracer, conf.Tracer)
+	assert.Equal(t, "repo/Get", conf.SpanNameFormatter("Repo", "Get"))
+	startConfig := trace.NewSpanStartConfig(conf.SpanStartOptions...)
+	assert.Equal(t, trace.SpanKindClient, startConfig.SpanKind())
+	assert.NotNil(t, conf.ErrorClassifier)
+	assert.Equal(t, context.TODO(), conf.FallbackContext)
+}
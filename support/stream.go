@@ -0,0 +1,121 @@
+package support
+
+import (
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"sync"
+)
+
+const (
+	// BytesReadKey is the attribute key of the number of bytes read from a traced reader
+	BytesReadKey = attribute.Key("otelwrap.stream.bytes_read")
+	// ItemCountKey is the attribute key of the number of items received from a traced channel or sequence
+	ItemCountKey = attribute.Key("otelwrap.stream.items")
+)
+
+type tracedReader struct {
+	reader io.Reader
+	span   trace.Span
+
+	mut       sync.Mutex
+	bytesRead int64
+	failed    bool
+	ended     bool
+}
+
+func (r *tracedReader) read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	r.bytesRead += int64(n)
+	if err != nil && !errors.Is(err, io.EOF) && !r.failed {
+		r.failed = true
+		r.span.RecordError(err)
+		r.span.SetStatus(codes.Error, err.Error())
+	}
+	return n, err
+}
+
+func (r *tracedReader) end(closeErr error) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if r.ended {
+		return
+	}
+	r.ended = true
+
+	if closeErr != nil && !r.failed {
+		r.span.RecordError(closeErr)
+		r.span.SetStatus(codes.Error, closeErr.Error())
+	}
+	r.span.SetAttributes(BytesReadKey.Int64(r.bytesRead))
+	r.span.End()
+}
+
+type tracedOnlyReader struct {
+	*tracedReader
+}
+
+func (r tracedOnlyReader) Read(p []byte) (int, error) {
+	n, err := r.read(p)
+	if err != nil {
+		r.end(nil)
+	}
+	return n, err
+}
+
+// TraceReader returns a reader ending the span when reading returns io.EOF or an error,
+// recording the number of bytes read
+func TraceReader(span trace.Span, reader io.Reader) io.Reader {
+	return tracedOnlyReader{
+		tracedReader: &tracedReader{reader: reader, span: span},
+	}
+}
+
+type tracedReadCloser struct {
+	*tracedReader
+	closer io.Closer
+}
+
+func (r tracedReadCloser) Read(p []byte) (int, error) {
+	return r.read(p)
+}
+
+func (r tracedReadCloser) Close() error {
+	err := r.closer.Close()
+	r.end(err)
+	return err
+}
+
+// TraceReadCloser returns a reader ending the span when it is closed,
+// recording the number of bytes read
+func TraceReadCloser(span trace.Span, reader io.ReadCloser) io.ReadCloser {
+	return tracedReadCloser{
+		tracedReader: &tracedReader{reader: reader, span: span},
+		closer:       reader,
+	}
+}
+
+// TraceChan returns a channel receiving the items of ch, ending the span when ch is closed.
+// The items must be received until the channel is closed, otherwise the forwarding goroutine is leaked
+func TraceChan[T any](span trace.Span, ch <-chan T) <-chan T {
+	out := make(chan T, cap(ch))
+	go func() {
+		defer close(out)
+
+		var count int64
+		for item := range ch {
+			count++
+			out <- item
+		}
+		span.SetAttributes(ItemCountKey.Int64(count))
+		span.End()
+	}()
+	return out
+}
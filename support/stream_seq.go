@@ -0,0 +1,30 @@
+//go:build go1.23
+
+package support
+
+import (
+	"go.opentelemetry.io/otel/trace"
+	"iter"
+	"sync"
+)
+
+// TraceSeq returns a sequence ending the span when the first iteration ends,
+// recording the number of items yielded.
+// The sequence must be iterated at least once, otherwise the span is never ended
+func TraceSeq[T any](span trace.Span, seq iter.Seq[T]) iter.Seq[T] {
+	var once sync.Once
+	return func(yield func(T) bool) {
+		var count int64
+		defer once.Do(func() {
+			span.SetAttributes(ItemCountKey.Int64(count))
+			span.End()
+		})
+
+		for item := range seq {
+			count++
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,38 @@
+//go:build go1.23
+
+package support
+
+import (
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"slices"
+	"testing"
+)
+
+func TestTraceSeq(t *testing.T) {
+	span := &fakeSpan{}
+	seq := TraceSeq(span, slices.Values([]string{"a", "b", "c"}))
+
+	var items []string
+	for item := range seq {
+		items = append(items, item)
+		if item == "b" {
+			break
+		}
+	}
+	assert.Equal(t, []string{"a", "b"}, items)
+	assert.Equal(t, 1, span.endCount)
+	assert.Equal(t, []attribute.KeyValue{ItemCountKey.Int64(2)}, span.attributes)
+
+	assert.Equal(t, []string{"a", "b", "c"}, slices.Collect(seq))
+	assert.Equal(t, 1, span.endCount)
+}
+
+func TestTraceSeq_Never_Iterated(t *testing.T) {
+	span := &fakeSpan{}
+	_ = TraceSeq(span, slices.Values([]string{"a", "b", "c"}))
+
+	// the span is only ended by iterating the sequence
+	assert.Equal(t, 0, span.endCount)
+	assert.Equal(t, []attribute.KeyValue(nil), span.attributes)
+}
@@ -0,0 +1,107 @@
+package support
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"io"
+	"strings"
+	"testing"
+)
+
+type fakeSpan struct {
+	noop.Span
+
+	endCount   int
+	attributes []attribute.KeyValue
+	errors     []error
+	status     codes.Code
+}
+
+func (s *fakeSpan) End(...trace.SpanEndOption) {
+	s.endCount++
+}
+
+func (s *fakeSpan) SetAttributes(kv ...attribute.KeyValue) {
+	s.attributes = append(s.attributes, kv...)
+}
+
+func (s *fakeSpan) RecordError(err error, _ ...trace.EventOption) {
+	s.errors = append(s.errors, err)
+}
+
+func (s *fakeSpan) SetStatus(code codes.Code, _ string) {
+	s.status = code
+}
+
+type errorReader struct {
+	err error
+}
+
+func (r errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+func TestTraceReader(t *testing.T) {
+	span := &fakeSpan{}
+	reader := TraceReader(span, strings.NewReader("hello"))
+
+	data, err := io.ReadAll(reader)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "hello", string(data))
+
+	assert.Equal(t, 1, span.endCount)
+	assert.Equal(t, []attribute.KeyValue{BytesReadKey.Int64(5)}, span.attributes)
+	assert.Equal(t, codes.Unset, span.status)
+}
+
+func TestTraceReader_Error(t *testing.T) {
+	span := &fakeSpan{}
+	readErr := errors.New("read error")
+	reader := TraceReader(span, errorReader{err: readErr})
+
+	_, err := reader.Read(make([]byte, 10))
+	assert.Equal(t, readErr, err)
+	_, _ = reader.Read(make([]byte, 10))
+
+	assert.Equal(t, 1, span.endCount)
+	assert.Equal(t, []error{readErr}, span.errors)
+	assert.Equal(t, codes.Error, span.status)
+}
+
+func TestTraceReadCloser(t *testing.T) {
+	span := &fakeSpan{}
+	reader := TraceReadCloser(span, io.NopCloser(strings.NewReader("hello")))
+
+	data, err := io.ReadAll(reader)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "hello", string(data))
+	assert.Equal(t, 0, span.endCount)
+
+	assert.Equal(t, nil, reader.Close())
+	assert.Equal(t, nil, reader.Close())
+
+	assert.Equal(t, 1, span.endCount)
+	assert.Equal(t, []attribute.KeyValue{BytesReadKey.Int64(5)}, span.attributes)
+}
+
+func TestTraceChan(t *testing.T) {
+	span := &fakeSpan{}
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	ch <- 3
+	close(ch)
+
+	var items []int
+	for item := range TraceChan(span, ch) {
+		items = append(items, item)
+	}
+	assert.Equal(t, []int{1, 2, 3}, items)
+
+	assert.Equal(t, 1, span.endCount)
+	assert.Equal(t, []attribute.KeyValue{ItemCountKey.Int64(3)}, span.attributes)
+}
@@ -0,0 +1,43 @@
+// Package support contains the types used by the code generated by otelwrap
+package support
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrorDisposition is how a wrapper handles an error returned by a wrapped method
+type ErrorDisposition int
+
+const (
+	// ErrorFailed records the error and sets the status of the span to codes.Error
+	ErrorFailed ErrorDisposition = iota
+	// ErrorRecorded records the error without changing the status of the span
+	ErrorRecorded
+	// ErrorIgnored neither records the error nor changes the status of the span
+	ErrorIgnored
+)
+
+// ErrorClassifier decides how an error returned by a wrapped method is handled
+type ErrorClassifier func(err error) ErrorDisposition
+
+// DefaultErrorClassifier does not fail spans of canceled calls, all other errors are failures
+func DefaultErrorClassifier(err error) ErrorDisposition {
+	if errors.Is(err, context.Canceled) {
+		return ErrorRecorded
+	}
+	return ErrorFailed
+}
+
+// IgnoreErrors returns a classifier ignoring errors that match any of the targets using errors.Is,
+// other errors are classified by next
+func IgnoreErrors(next ErrorClassifier, targets ...error) ErrorClassifier {
+	return func(err error) ErrorDisposition {
+		for _, target := range targets {
+			if errors.Is(err, target) {
+				return ErrorIgnored
+			}
+		}
+		return next(err)
+	}
+}
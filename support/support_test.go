@@ -0,0 +1,26 @@
+package support
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDefaultErrorClassifier(t *testing.T) {
+	assert.Equal(t, ErrorFailed, DefaultErrorClassifier(errors.New("some error")))
+	assert.Equal(t, ErrorRecorded, DefaultErrorClassifier(context.Canceled))
+	assert.Equal(t, ErrorRecorded, DefaultErrorClassifier(fmt.Errorf("wrapped: %w", context.Canceled)))
+	assert.Equal(t, ErrorFailed, DefaultErrorClassifier(context.DeadlineExceeded))
+}
+
+func TestIgnoreErrors(t *testing.T) {
+	errNotFound := errors.New("not found")
+	classifier := IgnoreErrors(DefaultErrorClassifier, errNotFound)
+
+	assert.Equal(t, ErrorIgnored, classifier(errNotFound))
+	assert.Equal(t, ErrorIgnored, classifier(fmt.Errorf("wrapped: %w", errNotFound)))
+	assert.Equal(t, ErrorRecorded, classifier(context.Canceled))
+	assert.Equal(t, ErrorFailed, classifier(errors.New("some error")))
+}
@@ -0,0 +1,24 @@
+package support
+
+// Wrapper is implemented by the wrappers generated by otelwrap
+type Wrapper interface {
+	// OtelwrapUnwrap returns the wrapped implementation, nil for a nil wrapper
+	OtelwrapUnwrap() any
+}
+
+// UnwrapAll peels nested wrappers generated by otelwrap and returns the innermost implementation,
+// other values are returned unchanged, even if they have an Unwrap method
+func UnwrapAll(v any) any {
+	for {
+		wrapper, ok := v.(Wrapper)
+		if !ok {
+			return v
+		}
+
+		next := wrapper.OtelwrapUnwrap()
+		if next == nil {
+			return v
+		}
+		v = next
+	}
+}
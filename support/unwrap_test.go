@@ -0,0 +1,68 @@
+package support
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type testGetter interface {
+	Get() int
+}
+
+type testGetterImpl struct{}
+
+func (testGetterImpl) Get() int {
+	return 1
+}
+
+type testGetterWrapper struct {
+	testGetter
+}
+
+func (w *testGetterWrapper) Unwrap() testGetter {
+	return w.testGetter
+}
+
+func (w *testGetterWrapper) OtelwrapUnwrap() any {
+	if w == nil {
+		return nil
+	}
+	return w.testGetter
+}
+
+type testOtherWrapper struct {
+	testGetter
+}
+
+func (w *testOtherWrapper) Unwrap() any {
+	return w.testGetter
+}
+
+func TestUnwrapAll(t *testing.T) {
+	impl := testGetterImpl{}
+
+	assert.Equal(t, impl, UnwrapAll(impl))
+	assert.Equal(t, impl, UnwrapAll(&testGetterWrapper{testGetter: impl}))
+	assert.Equal(t, impl, UnwrapAll(&testGetterWrapper{
+		testGetter: &testGetterWrapper{testGetter: impl},
+	}))
+
+	empty := &testGetterWrapper{}
+	assert.Same(t, empty, UnwrapAll(empty))
+
+	var nilWrapper *testGetterWrapper
+	assert.Equal(t, nilWrapper, UnwrapAll(nilWrapper))
+
+	assert.Equal(t, nil, UnwrapAll(nil))
+}
+
+func TestUnwrapAll_Only_Generated_Wrappers(t *testing.T) {
+	other := &testOtherWrapper{testGetter: testGetterImpl{}}
+	assert.Same(t, other, UnwrapAll(other))
+	assert.Same(t, other, UnwrapAll(&testGetterWrapper{testGetter: other}))
+
+	err := fmt.Errorf("wrapped: %w", errors.New("some error"))
+	assert.Equal(t, err, UnwrapAll(err))
+}